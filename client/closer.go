@@ -0,0 +1,156 @@
+package main
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	pb "github.com/Mohammad-Mahdi82/NexusOps/pkg/monitor"
+)
+
+const (
+	// defaultCloseTimeout applies when the server doesn't say how long a game may take to quit.
+	defaultCloseTimeout = 10 * time.Second
+	// forceWait is how long we wait for the OS to tear a process down after forcing it.
+	forceWait = 3 * time.Second
+)
+
+// closeReports holds close outcomes until the next heartbeat carries them to the server.
+var closeReports = &closeReportQueue{}
+
+type closeReportQueue struct {
+	mu      sync.Mutex
+	results []*pb.CloseResult
+}
+
+func (q *closeReportQueue) add(results ...*pb.CloseResult) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.results = append(q.results, results...)
+}
+
+// requeue puts back results a heartbeat failed to deliver, ahead of newer ones.
+func (q *closeReportQueue) requeue(results []*pb.CloseResult) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.results = append(append([]*pb.CloseResult(nil), results...), q.results...)
+}
+
+func (q *closeReportQueue) drain() []*pb.CloseResult {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	results := q.results
+	q.results = nil
+	return results
+}
+
+// closeGames closes each game's process tree, politely first, and reports what happened.
+func closeGames(probe ActivityProbe, targets []*pb.GameProcess, timeout time.Duration) []*pb.CloseResult {
+	results := make([]*pb.CloseResult, 0, len(targets))
+	for _, t := range targets {
+		results = append(results, closeTree(probe, t, timeout))
+	}
+	return results
+}
+
+// closeTree asks the game and everything it spawned to exit (WM_CLOSE / SIGTERM) so it
+// gets a chance to save, then kills whatever is still alive once the timeout runs out.
+func closeTree(probe ActivityProbe, target *pb.GameProcess, timeout time.Duration) *pb.CloseResult {
+	result := &pb.CloseResult{Pid: target.Pid, Name: target.Name}
+
+	procs := probe.Processes()
+	var root *Activity
+	for i := range procs {
+		if procs[i].PID == target.Pid {
+			root = &procs[i]
+			break
+		}
+	}
+	if root == nil || !sameProcess(*root, target) {
+		result.Outcome = pb.CloseResult_NOT_RUNNING
+		return result
+	}
+
+	tree := processTree(procs, root.PID)
+	requestClose(tree)
+	if waitForExit(tree, timeout) {
+		result.Outcome = pb.CloseResult_CLOSED
+		return result
+	}
+
+	for _, pid := range tree {
+		if processAlive(pid) {
+			if err := forceKill(pid); err != nil {
+				result.Error = err.Error()
+			}
+		}
+	}
+	if waitForExit(tree, forceWait) {
+		result.Outcome = pb.CloseResult_KILLED
+		return result
+	}
+	result.Outcome = pb.CloseResult_FAILED
+	return result
+}
+
+// sameProcess guards against PID reuse: the PID must still belong to the game the server saw.
+func sameProcess(a Activity, target *pb.GameProcess) bool {
+	if target.Name != "" && !strings.EqualFold(a.Name, target.Name) {
+		return false
+	}
+	if target.StartTime != 0 && !a.StartTime.IsZero() {
+		diff := a.StartTime.Unix() - target.StartTime
+		return diff >= -1 && diff <= 1
+	}
+	return true
+}
+
+// processTree returns root followed by all of its descendants. The OS reuses PIDs, so a
+// process whose real parent exited can name an unrelated one as its parent; a child only
+// counts when it started no earlier than its parent, and unknown start times never count.
+func processTree(procs []Activity, root uint32) []uint32 {
+	started := make(map[uint32]time.Time, len(procs))
+	for _, p := range procs {
+		started[p.PID] = p.StartTime
+	}
+	children := make(map[uint32][]uint32)
+	for _, p := range procs {
+		parentStart := started[p.ParentPID]
+		if p.PID == p.ParentPID || p.StartTime.IsZero() || parentStart.IsZero() || p.StartTime.Before(parentStart) {
+			continue
+		}
+		children[p.ParentPID] = append(children[p.ParentPID], p.PID)
+	}
+
+	tree := []uint32{root}
+	seen := map[uint32]bool{root: true}
+	for i := 0; i < len(tree); i++ {
+		for _, c := range children[tree[i]] {
+			if !seen[c] {
+				seen[c] = true
+				tree = append(tree, c)
+			}
+		}
+	}
+	return tree
+}
+
+func waitForExit(pids []uint32, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for {
+		alive := false
+		for _, pid := range pids {
+			if processAlive(pid) {
+				alive = true
+				break
+			}
+		}
+		if !alive {
+			return true
+		}
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(250 * time.Millisecond)
+	}
+}
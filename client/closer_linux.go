@@ -0,0 +1,30 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// requestClose sends SIGTERM, the Linux equivalent of asking a window to close.
+func requestClose(pids []uint32) {
+	for _, pid := range pids {
+		_ = syscall.Kill(int(pid), syscall.SIGTERM)
+	}
+}
+
+func forceKill(pid uint32) error {
+	return syscall.Kill(int(pid), syscall.SIGKILL)
+}
+
+// processAlive treats zombies as dead: they're gone, only waiting for their parent to reap them.
+func processAlive(pid uint32) bool {
+	data, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(int(pid)), "stat"))
+	if err != nil {
+		return false
+	}
+	i := strings.LastIndexByte(string(data), ')')
+	return i < 0 || !strings.HasPrefix(strings.TrimSpace(string(data[i+1:])), "Z")
+}
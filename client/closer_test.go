@@ -0,0 +1,84 @@
+package main
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	pb "github.com/Mohammad-Mahdi82/NexusOps/pkg/monitor"
+)
+
+func TestProcessTree(t *testing.T) {
+	base := time.Unix(1_700_000_000, 0)
+	at := func(minutes int) time.Time { return base.Add(time.Duration(minutes) * time.Minute) }
+
+	probe := &FakeProbe{}
+	probe.SetProcesses(
+		Activity{PID: 1, Name: "explorer.exe", StartTime: at(0)},
+		Activity{PID: 100, ParentPID: 1, Name: "launcher.exe", StartTime: at(10)},
+		Activity{PID: 110, ParentPID: 100, Name: "game.exe", StartTime: at(11)},
+		Activity{PID: 120, ParentPID: 110, Name: "crashpad.exe", StartTime: at(11)}, // Same second as its parent
+		Activity{PID: 130, ParentPID: 110, Name: "anticheat.exe", StartTime: at(12)},
+		// Started before 100 did, so 100 is a reused PID and not its parent
+		Activity{PID: 200, ParentPID: 100, Name: "updater.exe", StartTime: at(5)},
+		Activity{PID: 210, ParentPID: 100, Name: "unknown.exe"},                   // No start time
+		Activity{PID: 220, ParentPID: 999, Name: "orphan.exe", StartTime: at(20)}, // Parent is gone
+		Activity{PID: 300, ParentPID: 300, Name: "self.exe", StartTime: at(1)},
+	)
+
+	tests := []struct {
+		root uint32
+		want []uint32
+	}{
+		{root: 100, want: []uint32{100, 110, 120, 130}},
+		{root: 110, want: []uint32{110, 120, 130}},
+		{root: 130, want: []uint32{130}},
+		{root: 300, want: []uint32{300}},
+		{root: 999, want: []uint32{999}},
+	}
+	for _, tt := range tests {
+		got := processTree(probe.Processes(), tt.root)
+		slices.Sort(got[1:])
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("processTree(%d) = %v, want %v", tt.root, got, tt.want)
+		}
+	}
+}
+
+func TestSameProcess(t *testing.T) {
+	started := time.Unix(1_700_000_000, 0)
+	a := Activity{PID: 20, Name: "cs2.exe", StartTime: started}
+
+	tests := []struct {
+		name   string
+		target *pb.GameProcess
+		want   bool
+	}{
+		{name: "same game", target: &pb.GameProcess{Pid: 20, Name: "CS2.exe", StartTime: started.Unix()}, want: true},
+		{name: "start rounded", target: &pb.GameProcess{Pid: 20, Name: "cs2.exe", StartTime: started.Unix() + 1}, want: true},
+		{name: "PID reused later", target: &pb.GameProcess{Pid: 20, Name: "cs2.exe", StartTime: started.Unix() - 60}},
+		{name: "other image", target: &pb.GameProcess{Pid: 20, Name: "dota2.exe"}},
+		{name: "no start time sent", target: &pb.GameProcess{Pid: 20, Name: "cs2.exe"}, want: true},
+	}
+	for _, tt := range tests {
+		if got := sameProcess(a, tt.target); got != tt.want {
+			t.Errorf("%s: sameProcess() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCloseReportRequeue(t *testing.T) {
+	q := &closeReportQueue{}
+	q.add(&pb.CloseResult{Pid: 1})
+	sent := q.drain()
+	q.add(&pb.CloseResult{Pid: 2})
+	q.requeue(sent) // The heartbeat carrying 1 failed to send
+
+	got := q.drain()
+	if len(got) != 2 || got[0].Pid != 1 || got[1].Pid != 2 {
+		t.Errorf("drain() = %v, want PIDs 1 then 2", got)
+	}
+	if len(q.drain()) != 0 {
+		t.Error("drain() left results behind")
+	}
+}
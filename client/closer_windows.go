@@ -0,0 +1,66 @@
+package main
+
+import (
+	"golang.org/x/sys/windows"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+const wmClose = 0x0010
+
+var procPostMessage = user32.NewProc("PostMessageW")
+
+// EnumWindows callbacks can't be freed, so there is exactly one and it reports
+// into enumTargets, guarded by enumMu.
+var (
+	enumMu      sync.Mutex
+	enumOwners  map[uint32]bool
+	enumTargets []uintptr
+
+	enumCallback = syscall.NewCallback(func(hwnd uintptr, _ uintptr) uintptr {
+		var pid uint32
+		windows.GetWindowThreadProcessId(windows.HWND(hwnd), &pid)
+		if enumOwners[pid] {
+			enumTargets = append(enumTargets, hwnd)
+		}
+		return 1 // Keep enumerating
+	})
+)
+
+// requestClose posts WM_CLOSE to every top-level window owned by the tree, which is
+// what clicking the window's X does, so games get their usual save-and-quit path.
+func requestClose(pids []uint32) {
+	enumMu.Lock()
+	defer enumMu.Unlock()
+
+	enumOwners = make(map[uint32]bool, len(pids))
+	for _, pid := range pids {
+		enumOwners[pid] = true
+	}
+	enumTargets = nil
+	_ = windows.EnumWindows(enumCallback, unsafe.Pointer(nil))
+
+	for _, hwnd := range enumTargets {
+		procPostMessage.Call(hwnd, wmClose, 0, 0)
+	}
+}
+
+func forceKill(pid uint32) error {
+	h, err := windows.OpenProcess(windows.PROCESS_TERMINATE, false, pid)
+	if err != nil {
+		return err
+	}
+	defer windows.CloseHandle(h)
+	return windows.TerminateProcess(h, 1)
+}
+
+func processAlive(pid uint32) bool {
+	h, err := windows.OpenProcess(windows.SYNCHRONIZE, false, pid)
+	if err != nil {
+		return false
+	}
+	defer windows.CloseHandle(h)
+	event, err := windows.WaitForSingleObject(h, 0)
+	return err == nil && event == uint32(windows.WAIT_TIMEOUT)
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	pb "github.com/Mohammad-Mahdi82/NexusOps/pkg/monitor"
+)
+
+// fallbackServer is tried when discovery is off and no server is configured.
+const fallbackServer = "localhost:50051"
+
+// Config holds the Sentry settings loaded from sentry.json next to the executable.
+// Every field has a default so the file is optional.
+type Config struct {
+	Server           string          `json:"server"` // host:port, empty to find the server by mDNS
+	Discovery        DiscoveryConfig `json:"discovery"`
+	HeartbeatSeconds int             `json:"heartbeat_seconds"` // How often to look for changes worth reporting
+	KeepaliveSeconds int             `json:"keepalive_seconds"` // Report at least this often even if nothing changed
+	StationName      string          `json:"station_name"`      // Defaults to the hostname
+	LogPath          string          `json:"log_path"`          // Rotated when it grows, empty logs to stderr
+	LogLevel         string          `json:"log_level"`         // "debug", "info", "warn" or "error"
+	LogMaxSizeMB     int             `json:"log_max_size_mb"`
+	LogMaxBackups    int             `json:"log_max_backups"` // Rotated files to keep
+}
+
+type DiscoveryConfig struct {
+	Enabled        bool   `json:"enabled"`
+	TimeoutSeconds int    `json:"timeout_seconds"`
+	Site           string `json:"site"` // Only servers advertising the same site are used
+}
+
+func defaultConfig() Config {
+	return Config{
+		Discovery: DiscoveryConfig{
+			Enabled:        true,
+			TimeoutSeconds: 5,
+		},
+		HeartbeatSeconds: 2,
+		KeepaliveSeconds: 15,
+		LogPath:          "sentry.log",
+		LogLevel:         "info",
+		LogMaxSizeMB:     5,
+		LogMaxBackups:    3,
+	}
+}
+
+// LoadConfig reads the Sentry config, falling back to defaults when the file doesn't exist.
+func LoadConfig(path string) (Config, error) {
+	cfg := defaultConfig()
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, err
+	}
+	if cfg.HeartbeatSeconds <= 0 || cfg.KeepaliveSeconds <= 0 || cfg.Discovery.TimeoutSeconds <= 0 {
+		return cfg, errors.New("heartbeat_seconds, keepalive_seconds and discovery.timeout_seconds must be positive")
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(cfg.LogLevel)); err != nil {
+		return cfg, fmt.Errorf("log_level: %w", err)
+	}
+	return cfg, nil
+}
+
+// autostartCommand is the command line that starts the Sentry at login with the same
+// -config and -server flags it was started with.
+func autostartCommand(configPath, serverAddr string) string {
+	exePath, _ := os.Executable()
+	// Adding quotes around exePath is vital in case the user's name has a space
+	cmd := fmt.Sprintf("\"%s\"", exePath)
+	if configPath != "" {
+		abs, _ := filepath.Abs(configPath)
+		cmd += fmt.Sprintf(" -config \"%s\"", abs)
+	}
+	if serverAddr != "" {
+		cmd += fmt.Sprintf(" -server \"%s\"", serverAddr)
+	}
+	return cmd
+}
+
+func defaultConfigPath() string {
+	exePath, _ := os.Executable()
+	return filepath.Join(filepath.Dir(exePath), "sentry.json")
+}
+
+// resolvePath makes relative paths in the config relative to the executable.
+func resolvePath(p string) string {
+	if p == "" || filepath.IsAbs(p) {
+		return p
+	}
+	exePath, _ := os.Executable()
+	return filepath.Join(filepath.Dir(exePath), p)
+}
+
+// settings is the local config with whatever the server pushed on top of it.
+var settings = &clientSettings{}
+
+type clientSettings struct {
+	mu     sync.RWMutex
+	local  Config
+	pushed *pb.ClientSettings
+}
+
+// Load sets the values from the config file. Server overrides still win.
+func (c *clientSettings) Load(cfg Config) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.local = cfg
+}
+
+// Apply takes the overrides the server pushed over the stream.
+func (c *clientSettings) Apply(set *pb.ClientSettings) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pushed = set
+}
+
+// Version is reported in every heartbeat so the server knows when to resend.
+func (c *clientSettings) Version() int64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.pushed == nil {
+		return 0
+	}
+	return c.pushed.Version
+}
+
+func (c *clientSettings) Heartbeat() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.pushed != nil && c.pushed.HeartbeatIntervalSeconds > 0 {
+		return time.Duration(c.pushed.HeartbeatIntervalSeconds) * time.Second
+	}
+	return time.Duration(c.local.HeartbeatSeconds) * time.Second
+}
+
+func (c *clientSettings) Keepalive() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.pushed != nil && c.pushed.KeepaliveSeconds > 0 {
+		return time.Duration(c.pushed.KeepaliveSeconds) * time.Second
+	}
+	return time.Duration(c.local.KeepaliveSeconds) * time.Second
+}
+
+func (c *clientSettings) DiscoveryTimeout() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.pushed != nil && c.pushed.DiscoveryTimeoutSeconds > 0 {
+		return time.Duration(c.pushed.DiscoveryTimeoutSeconds) * time.Second
+	}
+	return time.Duration(c.local.Discovery.TimeoutSeconds) * time.Second
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAutostartCommand(t *testing.T) {
+	tests := []struct {
+		name       string
+		configPath string
+		serverAddr string
+		want       []string
+		notWant    []string
+	}{
+		{name: "no flags", notWant: []string{"-config", "-server"}},
+		{name: "server only", serverAddr: "192.168.1.10:50051", want: []string{`-server "192.168.1.10:50051"`}, notWant: []string{"-config"}},
+		{name: "both", configPath: "/etc/nexus/sentry.json", serverAddr: "pi.local:50051",
+			want: []string{`-config "/etc/nexus/sentry.json"`, `-server "pi.local:50051"`}},
+	}
+	for _, tt := range tests {
+		got := autostartCommand(tt.configPath, tt.serverAddr)
+		if !strings.HasPrefix(got, `"`) {
+			t.Errorf("%s: %s doesn't start with the quoted executable", tt.name, got)
+		}
+		for _, w := range tt.want {
+			if !strings.Contains(got, w) {
+				t.Errorf("%s: %s is missing %s", tt.name, got, w)
+			}
+		}
+		for _, w := range tt.notWant {
+			if strings.Contains(got, w) {
+				t.Errorf("%s: %s has %s", tt.name, got, w)
+			}
+		}
+	}
+}
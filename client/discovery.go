@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/grandcat/zeroconf"
+)
+
+// Server roles advertised in the beacon's TXT record.
+const (
+	RolePrimary = "primary"
+	RoleStandby = "standby"
+)
+
+// serverCandidate is one beacon answer, with the TXT metadata that decides the order we try them in.
+type serverCandidate struct {
+	Addr     string
+	Role     string
+	Priority int // Lower goes first within a role
+}
+
+// findServers collects every beacon of our site that answers within the discovery
+// timeout, best candidate first: primaries before standbys, then by priority.
+func findServers(site string) []serverCandidate {
+	// We use a timeout to not hang the app if the server is off
+	ctx, cancel := context.WithTimeout(context.Background(), settings.DiscoveryTimeout())
+	defer cancel()
+
+	// Passing 'nil' to NewResolver usually works, but on Windows
+	// it can be picky about which interface it uses.
+	resolver, err := zeroconf.NewResolver(nil)
+	if err != nil {
+		return nil
+	}
+
+	entries := make(chan *zeroconf.ServiceEntry)
+
+	// Start browsing for our specific service type
+	go func() {
+		err = resolver.Browse(ctx, "_nexusops._tcp", "local.", entries)
+		if err != nil {
+			slog.Warn("mDNS browse failed", "err", err)
+		}
+	}()
+
+	var answers []*zeroconf.ServiceEntry
+	for entry := range entries {
+		answers = append(answers, entry)
+	}
+	return rankServers(answers, site)
+}
+
+// rankServers turns beacon answers into candidates: answers from another site or without
+// an address are dropped, duplicates are merged, and the rest are sorted.
+func rankServers(entries []*zeroconf.ServiceEntry, site string) []serverCandidate {
+	seen := make(map[string]bool)
+	var found []serverCandidate
+	for _, entry := range entries {
+		var addr string
+		switch {
+		case len(entry.AddrIPv6) > 0:
+			// We use the Hostname provided by mDNS + the Port, e.g. 'NexusOps-Server.local:50051'.
+			// This lets Windows handle the Scope ID (%6) automatically!
+			addr = fmt.Sprintf("%s:%d", entry.HostName, entry.Port)
+		case len(entry.AddrIPv4) > 0:
+			addr = fmt.Sprintf("%s:%d", entry.AddrIPv4[0], entry.Port)
+		default:
+			continue
+		}
+		txt := parseTXT(entry.Text)
+		if txt["site"] != site {
+			continue // Another cafe, or a test rig, on the same LAN
+		}
+		if seen[addr] {
+			continue
+		}
+		seen[addr] = true
+
+		c := serverCandidate{Addr: addr, Role: RolePrimary}
+		if txt["role"] == RoleStandby {
+			c.Role = RoleStandby
+		}
+		c.Priority, _ = strconv.Atoi(txt["priority"])
+		found = append(found, c)
+	}
+
+	sort.SliceStable(found, func(i, j int) bool {
+		a, b := found[i], found[j]
+		if a.Role != b.Role {
+			return a.Role == RolePrimary
+		}
+		if a.Priority != b.Priority {
+			return a.Priority < b.Priority
+		}
+		return a.Addr < b.Addr
+	})
+	return found
+}
+
+// parseTXT splits key=value TXT strings. Servers that predate roles only send version.
+func parseTXT(text []string) map[string]string {
+	txt := make(map[string]string, len(text))
+	for _, kv := range text {
+		k, v, _ := strings.Cut(kv, "=")
+		txt[strings.ToLower(k)] = v
+	}
+	return txt
+}
+
+// serverTargets lists the addresses to try, in order: the configured server, or whatever
+// discovery found followed by the last server that worked, in case mDNS is blocked.
+func serverTargets(cfg Config) []string {
+	// Older autostart entries pass the fallback explicitly to mean "discover"
+	if cfg.Server != "" && cfg.Server != fallbackServer {
+		return []string{cfg.Server}
+	}
+
+	var found []serverCandidate
+	if cfg.Discovery.Enabled {
+		slog.Info("Searching for NexusOps server", "site", cfg.Discovery.Site)
+		found = findServers(cfg.Discovery.Site)
+	}
+	return orderTargets(cfg.Discovery.Enabled, found, loadLastServer())
+}
+
+// orderTargets puts the discovered servers first and the last server that worked after
+// them. With discovery off and nothing remembered, it falls back to localhost.
+func orderTargets(discovery bool, found []serverCandidate, last string) []string {
+	var targets []string
+	for _, c := range found {
+		targets = append(targets, c.Addr)
+	}
+	if last != "" && !slices.Contains(targets, last) {
+		targets = append(targets, last)
+	}
+	if len(targets) == 0 && !discovery {
+		targets = append(targets, fallbackServer)
+	}
+	return targets
+}
+
+func lastServerPath() string {
+	return resolvePath("last_server")
+}
+
+func loadLastServer() string {
+	data, err := os.ReadFile(lastServerPath())
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// saveLastServer remembers a server that accepted us, so a restart can reach it even
+// when discovery comes up empty.
+func saveLastServer(addr string) {
+	if loadLastServer() == addr {
+		return
+	}
+	if err := os.WriteFile(lastServerPath(), []byte(addr+"\n"), 0o644); err != nil {
+		slog.Warn("Could not remember server", "err", err)
+	}
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"net"
+	"slices"
+	"testing"
+
+	"github.com/grandcat/zeroconf"
+)
+
+func beacon(ip string, port int, txt ...string) *zeroconf.ServiceEntry {
+	e := zeroconf.NewServiceEntry("NexusOps", "_nexusops._tcp", "local.")
+	e.HostName = "nexus.local."
+	e.Port = port
+	e.Text = txt
+	if ip != "" {
+		e.AddrIPv4 = []net.IP{net.ParseIP(ip)}
+	}
+	return e
+}
+
+func TestRankServers(t *testing.T) {
+	ipv6 := beacon("", 50051, "site=cafe")
+	ipv6.AddrIPv6 = []net.IP{net.ParseIP("fe80::1")}
+
+	tests := []struct {
+		name    string
+		entries []*zeroconf.ServiceEntry
+		site    string
+		want    []string
+	}{
+		{
+			name: "primary before standby, then priority",
+			entries: []*zeroconf.ServiceEntry{
+				beacon("10.0.0.4", 50051, "role=standby", "priority=0"),
+				beacon("10.0.0.3", 50051, "role=primary", "priority=5"),
+				beacon("10.0.0.2", 50051, "role=standby", "priority=-1"),
+				beacon("10.0.0.1", 50051, "role=primary", "priority=1"),
+			},
+			want: []string{"10.0.0.1:50051", "10.0.0.3:50051", "10.0.0.2:50051", "10.0.0.4:50051"},
+		},
+		{
+			name: "old servers without a role are primaries",
+			entries: []*zeroconf.ServiceEntry{
+				beacon("10.0.0.2", 50051, "role=standby"),
+				beacon("10.0.0.1", 50051, "version=1"),
+			},
+			want: []string{"10.0.0.1:50051", "10.0.0.2:50051"},
+		},
+		{
+			name: "ties go by address",
+			entries: []*zeroconf.ServiceEntry{
+				beacon("10.0.0.9", 50051),
+				beacon("10.0.0.1", 50051),
+			},
+			want: []string{"10.0.0.1:50051", "10.0.0.9:50051"},
+		},
+		{
+			name: "only our site",
+			site: "cafe",
+			entries: []*zeroconf.ServiceEntry{
+				beacon("10.0.0.1", 50051),
+				beacon("10.0.0.2", 50051, "site=other"),
+				beacon("10.0.0.3", 50051, "SITE=cafe"),
+				beacon("10.0.0.4", 50051, "site=cafe", "role=standby"),
+			},
+			want: []string{"10.0.0.3:50051", "10.0.0.4:50051"},
+		},
+		{
+			name: "no site only takes servers without one",
+			entries: []*zeroconf.ServiceEntry{
+				beacon("10.0.0.1", 50051, "site=cafe"),
+				beacon("10.0.0.2", 50051, "site="),
+			},
+			want: []string{"10.0.0.2:50051"},
+		},
+		{
+			name: "duplicates and answers without an address",
+			site: "cafe",
+			entries: []*zeroconf.ServiceEntry{
+				beacon("10.0.0.1", 50051, "site=cafe"),
+				beacon("10.0.0.1", 50051, "site=cafe", "role=standby"),
+				beacon("", 50051, "site=cafe"),
+				ipv6,
+			},
+			want: []string{"10.0.0.1:50051", "nexus.local.:50051"},
+		},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, c := range rankServers(tt.entries, tt.site) {
+			got = append(got, c.Addr)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestOrderTargets(t *testing.T) {
+	found := []serverCandidate{{Addr: "10.0.0.1:50051", Role: RolePrimary}, {Addr: "10.0.0.2:50051", Role: RoleStandby}}
+
+	tests := []struct {
+		name      string
+		discovery bool
+		found     []serverCandidate
+		last      string
+		want      []string
+	}{
+		{name: "discovered only", discovery: true, found: found, want: []string{"10.0.0.1:50051", "10.0.0.2:50051"}},
+		{name: "last server after the discovered ones", discovery: true, found: found, last: "10.0.0.9:50051",
+			want: []string{"10.0.0.1:50051", "10.0.0.2:50051", "10.0.0.9:50051"}},
+		{name: "last server already discovered", discovery: true, found: found, last: "10.0.0.2:50051",
+			want: []string{"10.0.0.1:50051", "10.0.0.2:50051"}},
+		{name: "mDNS blocked", discovery: true, last: "10.0.0.9:50051", want: []string{"10.0.0.9:50051"}},
+		{name: "nothing found", discovery: true},
+		{name: "discovery off", want: []string{fallbackServer}},
+		{name: "discovery off with a last server", last: "10.0.0.9:50051", want: []string{"10.0.0.9:50051"}},
+	}
+	for _, tt := range tests {
+		if got := orderTargets(tt.discovery, tt.found, tt.last); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestServerTargetsConfigured(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.Server = "192.168.1.10:50051"
+	if got := serverTargets(cfg); !slices.Equal(got, []string{"192.168.1.10:50051"}) {
+		t.Errorf("serverTargets() = %v, want only the configured server", got)
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import "sync"
+
+// Locker covers the screen while the server says the PC isn't in a paid session.
+// Each OS provides one through newLocker; tests use FakeLocker.
+type Locker interface {
+	// Lock shows the lock screen with a message, or updates the message if already locked.
+	Lock(message string) error
+	Unlock() error
+	Locked() bool
+}
+
+// applyLock brings the locker in line with what the server asked for.
+func applyLock(l Locker, locked bool, message string) error {
+	if locked {
+		return l.Lock(message)
+	}
+	if l.Locked() {
+		return l.Unlock()
+	}
+	return nil
+}
+
+// FakeLocker only remembers its state. Also used on platforms without a lock screen.
+type FakeLocker struct {
+	mu      sync.Mutex
+	locked  bool
+	message string
+}
+
+func (f *FakeLocker) Lock(message string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.locked, f.message = true, message
+	return nil
+}
+
+func (f *FakeLocker) Unlock() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.locked, f.message = false, ""
+	return nil
+}
+
+func (f *FakeLocker) Locked() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.locked
+}
+
+func (f *FakeLocker) Message() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.message
+}
@@ -0,0 +1,28 @@
+package main
+
+import "testing"
+
+func TestApplyLock(t *testing.T) {
+	locker := &FakeLocker{}
+
+	steps := []struct {
+		name    string
+		locked  bool
+		message string
+	}{
+		{name: "unpaid PC locks", locked: true, message: "Please pay at the desk"},
+		{name: "new message while locked", locked: true, message: "Closing in 5 minutes"},
+		{name: "session started"},
+		{name: "still unlocked"},
+		{name: "locked again", locked: true},
+	}
+	for _, step := range steps {
+		if err := applyLock(locker, step.locked, step.message); err != nil {
+			t.Fatalf("%s: applyLock() error = %v", step.name, err)
+		}
+		if locker.Locked() != step.locked || locker.Message() != step.message {
+			t.Errorf("%s: locked %v with %q, want %v with %q",
+				step.name, locker.Locked(), locker.Message(), step.locked, step.message)
+		}
+	}
+}
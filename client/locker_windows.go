@@ -0,0 +1,306 @@
+package main
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	gdi32 = syscall.NewLazyDLL("gdi32.dll")
+
+	procRegisterClassEx   = user32.NewProc("RegisterClassExW")
+	procCreateWindowEx    = user32.NewProc("CreateWindowExW")
+	procDefWindowProc     = user32.NewProc("DefWindowProcW")
+	procDestroyWindow     = user32.NewProc("DestroyWindow")
+	procGetMessage        = user32.NewProc("GetMessageW")
+	procTranslateMessage  = user32.NewProc("TranslateMessage")
+	procDispatchMessage   = user32.NewProc("DispatchMessageW")
+	procPostQuitMessage   = user32.NewProc("PostQuitMessage")
+	procBeginPaint        = user32.NewProc("BeginPaint")
+	procEndPaint          = user32.NewProc("EndPaint")
+	procGetClientRect     = user32.NewProc("GetClientRect")
+	procFillRect          = user32.NewProc("FillRect")
+	procDrawText          = user32.NewProc("DrawTextW")
+	procInvalidateRect    = user32.NewProc("InvalidateRect")
+	procSetWindowPos      = user32.NewProc("SetWindowPos")
+	procSetForegroundWnd  = user32.NewProc("SetForegroundWindow")
+	procSetTimer          = user32.NewProc("SetTimer")
+	procGetSystemMetrics  = user32.NewProc("GetSystemMetrics")
+	procSetWindowsHookEx  = user32.NewProc("SetWindowsHookExW")
+	procCallNextHookEx    = user32.NewProc("CallNextHookEx")
+	procUnhookWindowsHook = user32.NewProc("UnhookWindowsHookEx")
+	procGetModuleHandle   = kernel32.NewProc("GetModuleHandleW")
+	procGetStockObject    = gdi32.NewProc("GetStockObject")
+	procSetTextColor      = gdi32.NewProc("SetTextColor")
+	procSetBkMode         = gdi32.NewProc("SetBkMode")
+	procCreateFont        = gdi32.NewProc("CreateFontW")
+	procSelectObject      = gdi32.NewProc("SelectObject")
+	procDeleteObject      = gdi32.NewProc("DeleteObject")
+)
+
+const (
+	wmDestroy = 0x0002
+	wmPaint   = 0x000F
+	wmTimer   = 0x0113
+	wmUnlock  = 0x8000 + 1 // WM_APP + 1
+
+	wsPopup        = 0x80000000
+	wsVisible      = 0x10000000
+	wsExTopmost    = 0x00000008
+	wsExToolWindow = 0x00000080
+
+	smXVirtualScreen  = 76
+	smYVirtualScreen  = 77
+	smCXVirtualScreen = 78
+	smCYVirtualScreen = 79
+
+	swpNoSize     = 0x0001
+	swpNoMove     = 0x0002
+	swpShowWindow = 0x0040
+
+	whKeyboardLL = 13
+	llkhfAltDown = 0x20
+	vkTab        = 0x09
+	vkEscape     = 0x1B
+	vkLWin       = 0x5B
+	vkRWin       = 0x5C
+
+	blackBrush = 4
+	dtFlags    = 0x1 | 0x4 | 0x20 // DT_CENTER | DT_VCENTER | DT_SINGLELINE
+)
+
+type winRect struct{ left, top, right, bottom int32 }
+
+type winMsg struct {
+	hwnd    uintptr
+	message uint32
+	wParam  uintptr
+	lParam  uintptr
+	time    uint32
+	pt      struct{ x, y int32 }
+	private uint32
+}
+
+type paintStruct struct {
+	hdc       uintptr
+	erase     int32
+	paint     winRect
+	restore   int32
+	incUpdate int32
+	reserved  [32]byte
+}
+
+type wndClassEx struct {
+	size       uint32
+	style      uint32
+	wndProc    uintptr
+	clsExtra   int32
+	wndExtra   int32
+	instance   uintptr
+	icon       uintptr
+	cursor     uintptr
+	background uintptr
+	menuName   *uint16
+	className  *uint16
+	iconSm     uintptr
+}
+
+type kbdLLHookStruct struct {
+	vkCode    uint32
+	scanCode  uint32
+	flags     uint32
+	time      uint32
+	extraInfo uintptr
+}
+
+// windowsLocker is a borderless, top-most window spanning every monitor, kept in front
+// by a timer, with a low-level keyboard hook that swallows the Windows key, Alt+Tab and
+// Alt+Esc. Ctrl+Alt+Del can't be blocked from user mode and stays available to staff.
+type windowsLocker struct {
+	mu   sync.Mutex
+	hwnd uintptr
+	done chan struct{}
+}
+
+// lockMessage is read by the window procedure, which runs on the lock screen's own thread.
+var lockMessage atomic.Pointer[uint16]
+
+var (
+	lockClassOnce sync.Once
+	lockClassErr  error
+	lockClassName = syscall.StringToUTF16Ptr("NexusOpsLockScreen")
+)
+
+func newLocker() Locker {
+	return &windowsLocker{}
+}
+
+func (l *windowsLocker) Lock(message string) error {
+	text, err := syscall.UTF16PtrFromString(message)
+	if err != nil {
+		return err
+	}
+	lockMessage.Store(text)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.hwnd != 0 {
+		procInvalidateRect.Call(l.hwnd, 0, 1)
+		return nil
+	}
+
+	ready := make(chan error, 1)
+	l.done = make(chan struct{})
+	go l.run(ready)
+	return <-ready
+}
+
+func (l *windowsLocker) Unlock() error {
+	l.mu.Lock()
+	hwnd, done := l.hwnd, l.done
+	l.mu.Unlock()
+	if hwnd == 0 {
+		return nil
+	}
+	procPostMessage.Call(hwnd, wmUnlock, 0, 0)
+	<-done
+	return nil
+}
+
+func (l *windowsLocker) Locked() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.hwnd != 0
+}
+
+// run owns the lock window: Win32 windows belong to the thread that created them,
+// so creation, the keyboard hook and the message loop all stay on one OS thread.
+func (l *windowsLocker) run(ready chan<- error) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	defer close(l.done)
+
+	instance, _, _ := procGetModuleHandle.Call(0)
+	lockClassOnce.Do(func() {
+		background, _, _ := procGetStockObject.Call(blackBrush)
+		class := wndClassEx{
+			wndProc:    lockWndProc,
+			instance:   instance,
+			background: background,
+			className:  lockClassName,
+		}
+		class.size = uint32(unsafe.Sizeof(class))
+		if atom, _, err := procRegisterClassEx.Call(uintptr(unsafe.Pointer(&class))); atom == 0 {
+			lockClassErr = err
+		}
+	})
+	if lockClassErr != nil {
+		ready <- lockClassErr
+		return
+	}
+
+	x, _, _ := procGetSystemMetrics.Call(smXVirtualScreen)
+	y, _, _ := procGetSystemMetrics.Call(smYVirtualScreen)
+	w, _, _ := procGetSystemMetrics.Call(smCXVirtualScreen)
+	h, _, _ := procGetSystemMetrics.Call(smCYVirtualScreen)
+	hwnd, _, err := procCreateWindowEx.Call(
+		wsExTopmost|wsExToolWindow,
+		uintptr(unsafe.Pointer(lockClassName)), uintptr(unsafe.Pointer(lockClassName)),
+		wsPopup|wsVisible,
+		uintptr(int32(x)), uintptr(int32(y)), w, h,
+		0, 0, instance, 0,
+	)
+	if hwnd == 0 {
+		ready <- errors.Join(errors.New("lock screen window"), err)
+		return
+	}
+
+	hook, _, _ := procSetWindowsHookEx.Call(whKeyboardLL, lockKeyboardHook, instance, 0)
+	procSetTimer.Call(hwnd, 1, 1000, 0)
+	procSetForegroundWnd.Call(hwnd)
+
+	l.hwnd = hwnd // Lock holds l.mu until ready is signalled
+	ready <- nil
+
+	var msg winMsg
+	for {
+		r, _, _ := procGetMessage.Call(uintptr(unsafe.Pointer(&msg)), 0, 0, 0)
+		if int32(r) <= 0 {
+			break
+		}
+		procTranslateMessage.Call(uintptr(unsafe.Pointer(&msg)))
+		procDispatchMessage.Call(uintptr(unsafe.Pointer(&msg)))
+	}
+
+	if hook != 0 {
+		procUnhookWindowsHook.Call(hook)
+	}
+	l.mu.Lock()
+	l.hwnd = 0
+	l.mu.Unlock()
+}
+
+var lockWndProc = syscall.NewCallback(func(hwnd uintptr, msg uint32, wParam, lParam uintptr) uintptr {
+	switch msg {
+	case wmPaint:
+		paintLockScreen(hwnd)
+		return 0
+	case wmTimer:
+		// Fullscreen games like to claim top-most too; keep taking it back
+		procSetWindowPos.Call(hwnd, ^uintptr(0), 0, 0, 0, 0, swpNoMove|swpNoSize|swpShowWindow)
+		procSetForegroundWnd.Call(hwnd)
+		return 0
+	case wmClose:
+		return 0 // Alt+F4 and our own close requests don't apply to the lock screen
+	case wmUnlock:
+		procDestroyWindow.Call(hwnd)
+		return 0
+	case wmDestroy:
+		procPostQuitMessage.Call(0)
+		return 0
+	}
+	r, _, _ := procDefWindowProc.Call(hwnd, uintptr(msg), wParam, lParam)
+	return r
+})
+
+func paintLockScreen(hwnd uintptr) {
+	var ps paintStruct
+	hdc, _, _ := procBeginPaint.Call(hwnd, uintptr(unsafe.Pointer(&ps)))
+	defer procEndPaint.Call(hwnd, uintptr(unsafe.Pointer(&ps)))
+
+	var rc winRect
+	procGetClientRect.Call(hwnd, uintptr(unsafe.Pointer(&rc)))
+	background, _, _ := procGetStockObject.Call(blackBrush)
+	procFillRect.Call(hdc, uintptr(unsafe.Pointer(&rc)), background)
+
+	text := lockMessage.Load()
+	if text == nil {
+		return
+	}
+	face := syscall.StringToUTF16Ptr("Segoe UI")
+	font, _, _ := procCreateFont.Call(48, 0, 0, 0, 600, 0, 0, 0, 1, 0, 0, 5, 0, uintptr(unsafe.Pointer(face)))
+	old, _, _ := procSelectObject.Call(hdc, font)
+	procSetTextColor.Call(hdc, 0x00FFFFFF)
+	procSetBkMode.Call(hdc, 1) // TRANSPARENT
+	procDrawText.Call(hdc, uintptr(unsafe.Pointer(text)), ^uintptr(0), uintptr(unsafe.Pointer(&rc)), dtFlags)
+	procSelectObject.Call(hdc, old)
+	procDeleteObject.Call(font)
+}
+
+var lockKeyboardHook = syscall.NewCallback(func(code int32, wParam uintptr, key *kbdLLHookStruct) uintptr {
+	if code >= 0 && key != nil {
+		alt := key.flags&llkhfAltDown != 0
+		switch {
+		case key.vkCode == vkLWin || key.vkCode == vkRWin:
+			return 1
+		case alt && (key.vkCode == vkTab || key.vkCode == vkEscape):
+			return 1
+		}
+	}
+	r, _, _ := procCallNextHookEx.Call(0, uintptr(code), wParam, uintptr(unsafe.Pointer(key)))
+	return r
+})
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"io"
+	"log/slog"
+	"os"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// setupLogging sends slog, and the stdlib log that libraries still use, to a rotating
+// file next to the executable. The Windows build has no console, so without a file
+// nobody would ever see why a PC won't connect.
+func setupLogging(cfg Config) {
+	var level slog.Level
+	level.UnmarshalText([]byte(cfg.LogLevel)) // Validated by LoadConfig
+
+	var w io.Writer = os.Stderr
+	if cfg.LogPath != "" {
+		w = &lumberjack.Logger{
+			Filename:   resolvePath(cfg.LogPath),
+			MaxSize:    cfg.LogMaxSizeMB,
+			MaxBackups: cfg.LogMaxBackups,
+		}
+	}
+	slog.SetDefault(slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{Level: level})))
+}
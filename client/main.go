@@ -2,22 +2,14 @@ package main
 
 import (
 	"flag"
+	"log"
 	"os"
-	"os/exec"
 )
 
-func setupClientFirewall() {
-	// 1. Wipe
-	exec.Command("netsh", "advfirewall", "firewall", "delete", "rule", "name=NexusOps_Client_Discovery").Run()
-
-	// 2. Apply
-	exec.Command("netsh", "advfirewall", "firewall", "add", "rule",
-		"name=NexusOps_Client_Discovery", "dir=in", "action=allow", "protocol=UDP", "localport=5353", "profile=any").Run()
-}
-
 func main() {
 	setupClientFirewall()
-	serverAddr := flag.String("server", "localhost:50051", "Server IP:Port")
+	configFlag := flag.String("config", "", "Config file (default sentry.json next to the executable)")
+	serverAddr := flag.String("server", "", "Server IP:Port, overrides the config file")
 	uninstall := flag.Bool("uninstall", false, "Remove from startup")
 	flag.Parse()
 
@@ -25,13 +17,30 @@ func main() {
 		handleUninstall()
 	}
 
+	configPath := *configFlag
+	if configPath == "" {
+		configPath = defaultConfigPath()
+	}
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		log.Fatal("Invalid config: ", err)
+	}
+	if *serverAddr != "" {
+		cfg.Server = *serverAddr
+	}
+	settings.Load(cfg)
+
+	setupLogging(cfg)
+
 	// Prevent double-running
 	if !createMutex("Global\\NexusOpsSentryMutex") {
 		os.Exit(0)
 	}
 
-	setAutoStart(*serverAddr)
+	// Flags are passed on as given, so a PC set up with -server keeps using that server
+	// after a reboot
+	setAutoStart(*configFlag, *serverAddr)
 
 	// Start the infinite communication loop
-	startResilientStream(*serverAddr)
+	startResilientStream(cfg, newActivityProbe(), newLocker())
 }
@@ -2,9 +2,7 @@ package main
 
 import (
 	"context"
-	"fmt"
-	"github.com/grandcat/zeroconf"
-	"log"
+	"log/slog"
 	"os"
 	"time"
 
@@ -13,116 +11,170 @@ import (
 	"google.golang.org/grpc/credentials/insecure"
 )
 
-// findServer scans the network for the beacon
-func findServer() string {
-	// We use a timeout to not hang the app if the server is off
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
-	defer cancel()
+// startResilientStream keeps the PC connected. It walks the candidate servers in order
+// and, once a connection drops, discovers again so a vanished primary fails over to a standby.
+func startResilientStream(cfg Config, probe ActivityProbe, locker Locker) {
+	pcName := cfg.StationName
+	if pcName == "" {
+		pcName, _ = os.Hostname()
+	}
+	for {
+		targets := serverTargets(cfg)
+		if len(targets) == 0 {
+			slog.Warn("No NexusOps server found, retrying")
+		}
 
-	// Passing 'nil' to NewResolver usually works, but on Windows
-	// it can be picky about which interface it uses.
-	resolver, err := zeroconf.NewResolver(nil)
-	if err != nil {
-		return ""
+		for _, targetAddr := range targets {
+			slog.Info("Connecting", "server", targetAddr)
+			conn, err := grpc.NewClient(targetAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if err != nil {
+				continue
+			}
+			connected := streamLogic(conn, targetAddr, pcName, probe, locker)
+			conn.Close()
+			if connected {
+				break // Rediscover: the server we had may be gone for good
+			}
+		}
+
+		time.Sleep(2 * time.Second)
 	}
+}
 
-	entries := make(chan *zeroconf.ServiceEntry)
+// streamLogic runs the session: heartbeats go out from this goroutine while commands
+// are handled as they arrive on another, so neither side waits on the other.
+// It reports whether the server ever answered, which makes it the last good server.
+func streamLogic(conn *grpc.ClientConn, addr, pcName string, probe ActivityProbe, locker Locker) bool {
+	client := pb.NewNexusServiceClient(conn)
 
-	// Start browsing for our specific service type
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := client.StreamSession(ctx)
+	if err != nil {
+		return false
+	}
+
+	// Receive Commands: the server sends them whenever it likes, not just after a heartbeat
+	done := make(chan struct{})
+	answered := make(chan struct{})
 	go func() {
-		err = resolver.Browse(ctx, "_nexusops._tcp", "local.", entries)
-		if err != nil {
-			log.Println("Browse error:", err)
+		defer close(done)
+		first := true
+		for {
+			resp, err := stream.Recv()
+			if err != nil {
+				// Connection is lost, let startResilientStream reconnect
+				return
+			}
+			if first {
+				first = false
+				slog.Info("Connected", "server", addr)
+				saveLastServer(addr)
+				close(answered)
+			}
+			handleCommand(resp, probe, locker)
 		}
 	}()
 
-	for entry := range entries {
-		// Here is where we handle your specific IPv6 situation correctly
-		if len(entry.AddrIPv6) > 0 {
-			// We use the Hostname provided by mDNS + the Port
-			// This is the "Correct" way. It uses 'NexusOps-Server.local:50051'
-			// This lets Windows handle the Scope ID (%6) automatically!
-			return fmt.Sprintf("%s:%d", entry.HostName, entry.Port)
+	var last *pb.Heartbeat
+	var lastSent time.Time
+	for {
+		// Send Heartbeat, but only when there's news or the keepalive is due
+		hb := &pb.Heartbeat{
+			PcId:            pcName,
+			CurrentGame:     currentGame(probe),
+			RulesVersion:    rules.Version(),
+			Games:           runningGames(probe),
+			CloseResults:    closeReports.drain(),
+			IdleSeconds:     uint32(probe.IdleTime().Seconds()),
+			SettingsVersion: settings.Version(),
+		}
+		if last == nil || heartbeatChanged(last, hb) || time.Since(lastSent) >= settings.Keepalive() {
+			hb.Timestamp = time.Now().Unix()
+			last, lastSent = hb, time.Now()
+			if err := stream.Send(hb); err != nil {
+				// If sending fails, the connection is likely dead.
+				// Return to let startResilientStream reconnect, which resends the close results.
+				closeReports.requeue(hb.CloseResults)
+				return isClosed(answered)
+			}
 		}
 
-		if len(entry.AddrIPv4) > 0 {
-			return fmt.Sprintf("%s:%d", entry.AddrIPv4[0], entry.Port)
+		select {
+		case <-done:
+			return isClosed(answered)
+		case <-time.After(settings.Heartbeat()):
 		}
 	}
-
-	return ""
 }
 
-func startResilientStream(manualAddr string) {
-	pcName, _ := os.Hostname()
-	for {
-		var targetAddr string
-
-		// If user didn't use the flag (it's default localhost or empty)
-		if manualAddr == "localhost:50051" || manualAddr == "" {
-			fmt.Println("Searching for NexusOps Server...")
-			discovered := findServer()
-			if discovered != "" {
-				targetAddr = discovered // Already has :50051 from findServer()
-			} else {
-				targetAddr = "localhost:50051" // Fallback
-			}
-		} else {
-			targetAddr = manualAddr
-		}
+func isClosed(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
 
-		fmt.Println("Attempting connection to:", targetAddr)
-		conn, err := grpc.NewClient(targetAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+// handleCommand applies one message from the server.
+func handleCommand(resp *pb.CommandResponse, probe ActivityProbe, locker Locker) {
+	if resp.ProcessRules != nil {
+		rules.Apply(resp.ProcessRules)
+	}
+	if resp.Settings != nil {
+		settings.Apply(resp.Settings)
+	}
+	handleClose(resp, probe)
+	if err := applyLock(locker, resp.Locked, resp.LockMessage); err != nil {
+		slog.Error("Lock screen failed", "err", err)
+	}
+	if resp.Notice != "" {
+		go showNotice(resp.Notice)
+	}
+}
 
-		if err == nil {
-			fmt.Println("Connected!")
-			streamLogic(conn, pcName)
-			conn.Close()
+// heartbeatChanged reports whether next tells the server anything prev didn't. Idle time
+// only counts when it crosses a whole minute, which is the granularity of the idle policy.
+func heartbeatChanged(prev, next *pb.Heartbeat) bool {
+	if prev.CurrentGame != next.CurrentGame ||
+		prev.RulesVersion != next.RulesVersion ||
+		prev.SettingsVersion != next.SettingsVersion ||
+		len(next.CloseResults) > 0 ||
+		prev.IdleSeconds/60 != next.IdleSeconds/60 {
+		return true
+	}
+	if len(prev.Games) != len(next.Games) {
+		return true
+	}
+	for i := range next.Games {
+		if prev.Games[i].Pid != next.Games[i].Pid || prev.Games[i].Focused != next.Games[i].Focused {
+			return true
 		}
-
-		time.Sleep(2 * time.Second)
 	}
+	return false
 }
 
-// streamLogic handles the bidirectional heartbeat and command reception.
-func streamLogic(conn *grpc.ClientConn, pcName string) {
-	client := pb.NewNexusServiceClient(conn)
-
-	// Use a context that we can cancel if needed, though here we use Background.
-	stream, err := client.StreamSession(context.Background())
-	if err != nil {
-		return
+// handleClose closes games in the background so a slow-to-quit game doesn't hold up heartbeats.
+// The outcome rides along with a later heartbeat.
+func handleClose(resp *pb.CommandResponse, probe ActivityProbe) {
+	timeout := defaultCloseTimeout
+	if resp.CloseTimeoutSeconds > 0 {
+		timeout = time.Duration(resp.CloseTimeoutSeconds) * time.Second
 	}
 
-	for {
-		currentGame := GetActiveProcessName()
-
-		// 1. Send Heartbeat
-		err := stream.Send(&pb.Heartbeat{
-			PcId:        pcName,
-			CurrentGame: currentGame,
-			Timestamp:   time.Now().Unix(),
-		})
-		if err != nil {
-			// If sending fails, the connection is likely dead.
-			// Break to let startResilientStream reconnect.
-			break
+	targets := resp.CloseGames
+	if len(targets) == 0 && resp.CloseActiveGame && currentGame(probe) != "Idle" {
+		// Older servers only say "close whatever is focused"
+		if a, ok := probe.Foreground(); ok {
+			targets = []*pb.GameProcess{toGameProcess(a, true)}
 		}
-
-		// 2. Receive Commands
-		// Recv() is blocking, but because the server sends a response
-		// for every heartbeat, it won't hang here.
-		resp, err := stream.Recv()
-		if err == nil {
-			if resp.CloseActiveGame && currentGame != "Idle" {
-				// Kill the game if the server signaled a "Close" (e.g., unpaid)
-				_ = runSilentCommand("taskkill", "/F", "/IM", currentGame).Run()
-			}
-		} else {
-			// If Recv fails, connection is lost.
-			break
-		}
-
-		time.Sleep(2 * time.Second)
 	}
+	if len(targets) == 0 {
+		return
+	}
+
+	go func() {
+		closeReports.add(closeGames(probe, targets, timeout)...)
+	}()
 }
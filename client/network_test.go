@@ -0,0 +1,50 @@
+package main
+
+import (
+	"testing"
+
+	pb "github.com/Mohammad-Mahdi82/NexusOps/pkg/monitor"
+)
+
+func TestHeartbeatChanged(t *testing.T) {
+	heartbeat := func(change func(hb *pb.Heartbeat)) *pb.Heartbeat {
+		hb := &pb.Heartbeat{
+			PcId:         "PC-01",
+			CurrentGame:  "cs2.exe",
+			RulesVersion: 7,
+			Games:        []*pb.GameProcess{{Pid: 20, Name: "cs2.exe", Focused: true}},
+			IdleSeconds:  30,
+		}
+		change(hb)
+		return hb
+	}
+	prev := heartbeat(func(*pb.Heartbeat) {})
+
+	tests := []struct {
+		name    string
+		change  func(hb *pb.Heartbeat)
+		changed bool
+	}{
+		{name: "nothing new", change: func(*pb.Heartbeat) {}},
+		{name: "idle within the same minute", change: func(hb *pb.Heartbeat) { hb.IdleSeconds = 50 }},
+		{name: "idle crosses a minute", change: func(hb *pb.Heartbeat) { hb.IdleSeconds = 61 }, changed: true},
+		{name: "focus leaves the game", change: func(hb *pb.Heartbeat) {
+			hb.CurrentGame = "Idle"
+			hb.Games[0].Focused = false
+		}, changed: true},
+		{name: "game restarted", change: func(hb *pb.Heartbeat) { hb.Games[0].Pid = 21 }, changed: true},
+		{name: "second game", change: func(hb *pb.Heartbeat) {
+			hb.Games = append(hb.Games, &pb.GameProcess{Pid: 30, Name: "dota2.exe"})
+		}, changed: true},
+		{name: "new rules", change: func(hb *pb.Heartbeat) { hb.RulesVersion = 8 }, changed: true},
+		{name: "new settings", change: func(hb *pb.Heartbeat) { hb.SettingsVersion = 2 }, changed: true},
+		{name: "close result waiting", change: func(hb *pb.Heartbeat) {
+			hb.CloseResults = []*pb.CloseResult{{Pid: 20, Outcome: pb.CloseResult_CLOSED}}
+		}, changed: true},
+	}
+	for _, tt := range tests {
+		if got := heartbeatChanged(prev, heartbeat(tt.change)); got != tt.changed {
+			t.Errorf("%s: heartbeatChanged() = %v, want %v", tt.name, got, tt.changed)
+		}
+	}
+}
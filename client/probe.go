@@ -0,0 +1,126 @@
+package main
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	pb "github.com/Mohammad-Mahdi82/NexusOps/pkg/monitor"
+)
+
+// Activity describes a process: the one owning the focused window, or one of the running ones.
+type Activity struct {
+	PID       uint32
+	ParentPID uint32
+	Name      string // Image name, e.g. cs2.exe
+	Path      string // Full image path when the OS lets us read it
+	StartTime time.Time
+}
+
+// ActivityProbe finds out what the user is looking at and what is running. Each OS
+// provides one through newActivityProbe; tests use FakeProbe.
+type ActivityProbe interface {
+	// Foreground returns the focused process, or false when nothing is focused.
+	Foreground() (Activity, bool)
+	// Processes lists every process the probe can see.
+	Processes() []Activity
+	// IdleTime is how long ago the last keyboard or mouse input happened, 0 if unknown.
+	IdleTime() time.Duration
+}
+
+// currentGame turns the probe's answer into what the server bills: a game name or "Idle".
+func currentGame(probe ActivityProbe) string {
+	a, ok := probe.Foreground()
+	if !ok || rules.Ignored(a.Name, a.Path) {
+		return "Idle"
+	}
+	return a.Name
+}
+
+// runningGames reports every process a rule tracks as a game, plus the focused process
+// unless a rule ignores it, so the server can keep billing a game the user alt-tabbed away from.
+func runningGames(probe ActivityProbe) []*pb.GameProcess {
+	focused, hasFocus := probe.Foreground()
+	if hasFocus && rules.Ignored(focused.Name, focused.Path) {
+		hasFocus = false
+	}
+
+	var games []*pb.GameProcess
+	for _, p := range probe.Processes() {
+		isFocused := hasFocus && p.PID == focused.PID
+		if !isFocused && !rules.Tracked(p.Name, p.Path) {
+			continue
+		}
+		games = append(games, toGameProcess(p, isFocused))
+		if isFocused {
+			hasFocus = false // Already reported
+		}
+	}
+	if hasFocus {
+		games = append(games, toGameProcess(focused, true))
+	}
+
+	sort.Slice(games, func(i, j int) bool { return games[i].StartTime < games[j].StartTime })
+	return games
+}
+
+func toGameProcess(a Activity, focused bool) *pb.GameProcess {
+	g := &pb.GameProcess{Pid: a.PID, ParentPid: a.ParentPID, Name: a.Name, Path: a.Path, Focused: focused}
+	if !a.StartTime.IsZero() {
+		g.StartTime = a.StartTime.Unix()
+	}
+	return g
+}
+
+// FakeProbe reports whatever activity it was last given. It never touches the OS.
+type FakeProbe struct {
+	mu        sync.Mutex
+	activity  Activity
+	focused   bool
+	processes []Activity
+	idle      time.Duration
+}
+
+func (f *FakeProbe) Set(a Activity) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.activity, f.focused = a, true
+}
+
+// Clear simulates the desktop having no focused window.
+func (f *FakeProbe) Clear() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.activity, f.focused = Activity{}, false
+}
+
+func (f *FakeProbe) Foreground() (Activity, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.activity, f.focused
+}
+
+// SetProcesses replaces the list of running processes.
+func (f *FakeProbe) SetProcesses(processes ...Activity) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.processes = processes
+}
+
+func (f *FakeProbe) Processes() []Activity {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Activity(nil), f.processes...)
+}
+
+func (f *FakeProbe) SetIdle(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.idle = d
+}
+
+func (f *FakeProbe) IdleTime() time.Duration {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.idle
+}
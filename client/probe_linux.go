@@ -0,0 +1,227 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// linuxProbe finds the focused window's PID through whichever desktop is running,
+// then resolves the executable through /proc.
+//
+//   - X11 (and XWayland games): xprop's _NET_ACTIVE_WINDOW and _NET_WM_PID
+//   - Sway: the focused node in `swaymsg -t get_tree`
+//   - Hyprland: `hyprctl activewindow -j`
+//
+// Other Wayland compositors don't expose focus to clients, so they report Idle.
+type linuxProbe struct{}
+
+func newActivityProbe() ActivityProbe {
+	return linuxProbe{}
+}
+
+func (linuxProbe) Foreground() (Activity, bool) {
+	var pid int
+	var err error
+	switch {
+	case os.Getenv("SWAYSOCK") != "":
+		pid, err = swayFocusedPID()
+	case os.Getenv("HYPRLAND_INSTANCE_SIGNATURE") != "":
+		pid, err = hyprlandFocusedPID()
+	case os.Getenv("DISPLAY") != "":
+		pid, err = x11FocusedPID()
+	default:
+		return Activity{}, false
+	}
+	if err != nil || pid <= 0 {
+		return Activity{}, false
+	}
+	return procActivity(pid)
+}
+
+// Processes lists every process in /proc.
+func (linuxProbe) Processes() []Activity {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil
+	}
+	var list []Activity
+	for _, e := range entries {
+		pid, err := strconv.Atoi(e.Name())
+		if err != nil {
+			continue
+		}
+		if a, ok := procActivity(pid); ok {
+			list = append(list, a)
+		}
+	}
+	return list
+}
+
+// IdleTime uses xprintidle on X11. Wayland compositors only expose idleness through
+// protocols a background process can't use, so there it stays unknown.
+func (linuxProbe) IdleTime() time.Duration {
+	if os.Getenv("DISPLAY") == "" {
+		return 0
+	}
+	out, err := exec.Command("xprintidle").Output()
+	if err != nil {
+		return 0
+	}
+	ms, err := strconv.ParseInt(strings.TrimSpace(string(out)), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return time.Duration(ms) * time.Millisecond
+}
+
+// procActivity reads a process's executable from /proc. exe can be unreadable for
+// other users' processes, in which case comm (truncated to 15 chars) is the best we have.
+func procActivity(pid int) (Activity, bool) {
+	base := filepath.Join("/proc", strconv.Itoa(pid))
+	a := Activity{PID: uint32(pid)}
+
+	if path, err := os.Readlink(filepath.Join(base, "exe")); err == nil {
+		a.Path = strings.TrimSuffix(path, " (deleted)")
+		a.Name = filepath.Base(a.Path)
+	} else if comm, err := os.ReadFile(filepath.Join(base, "comm")); err == nil {
+		a.Name = strings.TrimSpace(string(comm))
+	} else {
+		return Activity{}, false
+	}
+
+	a.ParentPID, a.StartTime = procStat(base)
+	return a, true
+}
+
+// clockTicks is USER_HZ, which is 100 on every Linux architecture we ship to.
+const clockTicks = 100
+
+// procStat pulls the parent PID and start time out of /proc/<pid>/stat.
+func procStat(base string) (uint32, time.Time) {
+	data, err := os.ReadFile(filepath.Join(base, "stat"))
+	if err != nil {
+		return 0, time.Time{}
+	}
+	// "1234 (game name) S 1 ..." - comm can contain spaces, so split after the last ')'
+	i := strings.LastIndexByte(string(data), ')')
+	if i < 0 {
+		return 0, time.Time{}
+	}
+	fields := strings.Fields(string(data[i+1:]))
+	if len(fields) < 20 {
+		return 0, time.Time{}
+	}
+	ppid, _ := strconv.ParseUint(fields[1], 10, 32)
+	ticks, _ := strconv.ParseInt(fields[19], 10, 64)
+
+	boot := bootTime()
+	if boot.IsZero() {
+		return uint32(ppid), time.Time{}
+	}
+	return uint32(ppid), boot.Add(time.Duration(ticks) * time.Second / clockTicks)
+}
+
+var (
+	bootOnce sync.Once
+	booted   time.Time
+)
+
+// bootTime reads btime from /proc/stat once; process start times are relative to it.
+func bootTime() time.Time {
+	bootOnce.Do(func() {
+		data, err := os.ReadFile("/proc/stat")
+		if err != nil {
+			return
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			if v, ok := strings.CutPrefix(line, "btime "); ok {
+				if sec, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64); err == nil {
+					booted = time.Unix(sec, 0)
+				}
+			}
+		}
+	})
+	return booted
+}
+
+func x11FocusedPID() (int, error) {
+	// _NET_ACTIVE_WINDOW(WINDOW): window id # 0x3c00007
+	out, err := exec.Command("xprop", "-root", "_NET_ACTIVE_WINDOW").Output()
+	if err != nil {
+		return 0, err
+	}
+	fields := strings.Fields(string(out))
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("unexpected xprop output %q", out)
+	}
+	window := fields[len(fields)-1]
+	if window == "0x0" {
+		return 0, nil
+	}
+
+	// _NET_WM_PID(CARDINAL) = 12345
+	out, err = exec.Command("xprop", "-id", window, "_NET_WM_PID").Output()
+	if err != nil {
+		return 0, err
+	}
+	_, value, found := strings.Cut(string(out), "=")
+	if !found {
+		return 0, fmt.Errorf("window %s has no _NET_WM_PID", window)
+	}
+	return strconv.Atoi(strings.TrimSpace(value))
+}
+
+func swayFocusedPID() (int, error) {
+	out, err := exec.Command("swaymsg", "-t", "get_tree").Output()
+	if err != nil {
+		return 0, err
+	}
+
+	type node struct {
+		Focused       bool   `json:"focused"`
+		PID           int    `json:"pid"`
+		Nodes         []node `json:"nodes"`
+		FloatingNodes []node `json:"floating_nodes"`
+	}
+	var root node
+	if err := json.Unmarshal(out, &root); err != nil {
+		return 0, err
+	}
+
+	var find func(n node) int
+	find = func(n node) int {
+		if n.Focused && n.PID > 0 {
+			return n.PID
+		}
+		for _, children := range [][]node{n.Nodes, n.FloatingNodes} {
+			for _, c := range children {
+				if pid := find(c); pid > 0 {
+					return pid
+				}
+			}
+		}
+		return 0
+	}
+	return find(root), nil
+}
+
+func hyprlandFocusedPID() (int, error) {
+	out, err := exec.Command("hyprctl", "activewindow", "-j").Output()
+	if err != nil {
+		return 0, err
+	}
+	var window struct {
+		PID int `json:"pid"`
+	}
+	if err := json.Unmarshal(out, &window); err != nil {
+		return 0, err
+	}
+	return window.PID, nil
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	pb "github.com/Mohammad-Mahdi82/NexusOps/pkg/monitor"
+)
+
+// useRules swaps in a rule set for one test; a nil set keeps the fallback list.
+func useRules(t *testing.T, set *pb.ProcessRules) {
+	t.Helper()
+	saved := rules
+	rules = newProcessRules()
+	if set != nil {
+		rules.Apply(set)
+	}
+	t.Cleanup(func() { rules = saved })
+}
+
+func TestProcessRules(t *testing.T) {
+	useRules(t, &pb.ProcessRules{Version: 3, Rules: []*pb.ProcessRule{
+		{Pattern: `C:\Games\Tools\*`, Match: pb.ProcessRule_PATH_GLOB},
+		{Pattern: `C:\Games\*`, Match: pb.ProcessRule_PATH_GLOB, Action: pb.ProcessRule_TRACK},
+		{Pattern: "steam*.exe"},
+		{Pattern: `\\Riot Games\\.*\.exe$`, Match: pb.ProcessRule_REGEX, Action: pb.ProcessRule_TRACK},
+		{Pattern: "([", Match: pb.ProcessRule_REGEX}, // Doesn't compile, so it's skipped
+	}})
+	if v := rules.Version(); v != 3 {
+		t.Errorf("Version() = %d, want 3", v)
+	}
+
+	tests := []struct {
+		name, path       string
+		ignored, tracked bool
+	}{
+		{name: "cs2.exe", path: `C:\Games\CS2\cs2.exe`, tracked: true},
+		{name: "CS2.EXE", path: `c:\games\cs2\CS2.EXE`, tracked: true},
+		{name: "benchmark.exe", path: `C:\Games\Tools\benchmark.exe`, ignored: true},
+		{name: "SteamWebHelper.exe", path: `C:\Steam\steamwebhelper.exe`, ignored: true},
+		{name: "valorant.exe", path: `D:\Riot Games\VALORANT\valorant.exe`, tracked: true},
+		{name: "notepad.exe", path: `C:\Windows\notepad.exe`},
+		{name: "notepad.exe"}, // Path rules never match an unknown path
+		{ignored: true},       // No name means no window worth billing
+	}
+	for _, tt := range tests {
+		if got := rules.Ignored(tt.name, tt.path); got != tt.ignored {
+			t.Errorf("Ignored(%q, %q) = %v, want %v", tt.name, tt.path, got, tt.ignored)
+		}
+		if got := rules.Tracked(tt.name, tt.path); got != tt.tracked {
+			t.Errorf("Tracked(%q, %q) = %v, want %v", tt.name, tt.path, got, tt.tracked)
+		}
+	}
+}
+
+func TestFallbackRules(t *testing.T) {
+	useRules(t, nil)
+	if !rules.Ignored("Explorer.EXE", "") || !rules.Ignored("discord.exe", "") {
+		t.Error("the fallback list should ignore the shell and launchers")
+	}
+	if rules.Ignored("cs2.exe", "") || rules.Tracked("cs2.exe", "") {
+		t.Error("the fallback list should neither ignore nor track a game")
+	}
+}
+
+func TestCurrentGame(t *testing.T) {
+	useRules(t, nil)
+	probe := &FakeProbe{}
+	if got := currentGame(probe); got != "Idle" {
+		t.Errorf("nothing focused: currentGame() = %q, want Idle", got)
+	}
+	probe.Set(Activity{PID: 10, Name: "explorer.exe"})
+	if got := currentGame(probe); got != "Idle" {
+		t.Errorf("explorer focused: currentGame() = %q, want Idle", got)
+	}
+	probe.Set(Activity{PID: 20, Name: "cs2.exe"})
+	if got := currentGame(probe); got != "cs2.exe" {
+		t.Errorf("game focused: currentGame() = %q, want cs2.exe", got)
+	}
+	probe.Clear()
+	if got := currentGame(probe); got != "Idle" {
+		t.Errorf("after Clear: currentGame() = %q, want Idle", got)
+	}
+}
+
+func TestRunningGames(t *testing.T) {
+	useRules(t, &pb.ProcessRules{Rules: []*pb.ProcessRule{
+		{Pattern: `C:\Games\*`, Match: pb.ProcessRule_PATH_GLOB, Action: pb.ProcessRule_TRACK},
+		{Pattern: "explorer.exe"},
+	}})
+	base := time.Unix(1_700_000_000, 0)
+	game := Activity{PID: 20, ParentPID: 1, Name: "cs2.exe", Path: `C:\Games\CS2\cs2.exe`, StartTime: base.Add(time.Minute)}
+	other := Activity{PID: 30, ParentPID: 1, Name: "dota2.exe", Path: `C:\Games\Dota\dota2.exe`, StartTime: base}
+	editor := Activity{PID: 40, ParentPID: 1, Name: "notepad.exe", Path: `C:\Windows\notepad.exe`, StartTime: base}
+
+	probe := &FakeProbe{}
+	probe.SetProcesses(game, other, editor, Activity{PID: 10, Name: "explorer.exe"})
+
+	// Both games run in the background, oldest first; notepad isn't one
+	got := runningGames(probe)
+	if len(got) != 2 || got[0].Pid != 30 || got[1].Pid != 20 || got[0].Focused || got[1].Focused {
+		t.Fatalf("background: runningGames() = %v", got)
+	}
+	if got[1].StartTime != game.StartTime.Unix() || got[1].Path != game.Path {
+		t.Errorf("game reported as %v", got[1])
+	}
+
+	// Whatever has focus is reported once, even when no rule tracks it
+	probe.Set(editor)
+	got = runningGames(probe)
+	if len(got) != 3 {
+		t.Fatalf("notepad focused: runningGames() = %v", got)
+	}
+	focused := 0
+	for _, g := range got {
+		if g.Focused {
+			focused++
+			if g.Pid != 40 {
+				t.Errorf("focused %v, want notepad", g)
+			}
+		}
+	}
+	if focused != 1 {
+		t.Errorf("%d focused games, want 1", focused)
+	}
+
+	// An ignored window doesn't count as focus
+	probe.Set(Activity{PID: 10, Name: "explorer.exe"})
+	for _, g := range runningGames(probe) {
+		if g.Focused || g.Pid == 10 {
+			t.Errorf("explorer reported as %v", g)
+		}
+	}
+}
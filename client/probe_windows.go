@@ -0,0 +1,195 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	user32                       = syscall.NewLazyDLL("user32.dll")
+	procGetForegroundWindow      = user32.NewProc("GetForegroundWindow")
+	procGetWindowThreadProcessId = user32.NewProc("GetWindowThreadProcessId")
+	procGetLastInputInfo         = user32.NewProc("GetLastInputInfo")
+	procGetTickCount             = kernel32.NewProc("GetTickCount")
+)
+
+// windowsProbe asks user32 for the foreground window and resolves its process.
+// Image paths are cached per process, so each heartbeat only opens the processes that
+// started since the last one.
+type windowsProbe struct {
+	mu    sync.Mutex
+	paths map[processKey]string
+}
+
+// processKey names one process: a PID is only reused after its process exited, and the
+// new one has a later creation time.
+type processKey struct {
+	pid     uint32
+	created int64
+}
+
+func newActivityProbe() ActivityProbe {
+	return &windowsProbe{paths: make(map[processKey]string)}
+}
+
+func (*windowsProbe) Foreground() (Activity, bool) {
+	hwnd, _, _ := procGetForegroundWindow.Call()
+	if hwnd == 0 {
+		return Activity{}, false
+	}
+
+	var processID uint32
+	procGetWindowThreadProcessId.Call(hwnd, uintptr(unsafe.Pointer(&processID)))
+
+	path, started := processDetails(processID)
+	name := filepath.Base(path)
+	if path == "" {
+		name = processNameFromTasklist(processID)
+	}
+	if name == "" {
+		return Activity{}, false
+	}
+	return Activity{PID: processID, Name: name, Path: path, StartTime: started}, true
+}
+
+// Processes lists every process with one NtQuerySystemInformation call, which also
+// gives creation times. Only processes we haven't seen before get opened for their path.
+func (w *windowsProbe) Processes() []Activity {
+	infos, err := systemProcesses()
+	if err != nil {
+		return nil
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	seen := make(map[processKey]string, len(infos))
+	list := make([]Activity, 0, len(infos))
+	for _, info := range infos {
+		key := processKey{pid: uint32(info.UniqueProcessID), created: info.CreateTime}
+		path, ok := w.paths[key]
+		if !ok {
+			path = processPath(key.pid) // Also cached when empty, so we don't retry processes we can't open
+		}
+		seen[key] = path
+
+		a := Activity{
+			PID:       key.pid,
+			ParentPID: uint32(info.InheritedFromUniqueProcessID),
+			Name:      info.ImageName.String(),
+			Path:      path,
+		}
+		if info.CreateTime != 0 {
+			a.StartTime = time.Unix(0, filetimeNanos(info.CreateTime))
+		}
+		list = append(list, a)
+	}
+	w.paths = seen // Drops processes that exited
+	return list
+}
+
+// systemProcesses reads the SystemProcessInformation list, growing the buffer until it fits.
+func systemProcesses() ([]windows.SYSTEM_PROCESS_INFORMATION, error) {
+	size := uint32(256 << 10)
+	for {
+		buf := make([]uint64, size/8+1) // uint64 keeps the entries aligned
+		var needed uint32
+		err := windows.NtQuerySystemInformation(windows.SystemProcessInformation, unsafe.Pointer(&buf[0]), uint32(len(buf)*8), &needed)
+		if err == windows.STATUS_INFO_LENGTH_MISMATCH {
+			size = needed + 64<<10 // Processes may start before the next try
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var infos []windows.SYSTEM_PROCESS_INFORMATION
+		base := unsafe.Pointer(&buf[0])
+		for offset := uintptr(0); ; {
+			info := (*windows.SYSTEM_PROCESS_INFORMATION)(unsafe.Add(base, offset))
+			infos = append(infos, *info)
+			if info.NextEntryOffset == 0 {
+				break
+			}
+			offset += uintptr(info.NextEntryOffset)
+		}
+		return infos, nil
+	}
+}
+
+// filetimeNanos converts a FILETIME count to Unix nanoseconds.
+func filetimeNanos(ft int64) int64 {
+	t := windows.Filetime{LowDateTime: uint32(ft), HighDateTime: uint32(ft >> 32)}
+	return t.Nanoseconds()
+}
+
+// IdleTime compares the tick of the last input event with the current tick.
+// Both wrap every 49.7 days, which uint32 subtraction handles.
+func (*windowsProbe) IdleTime() time.Duration {
+	var info struct {
+		cbSize uint32
+		dwTime uint32
+	}
+	info.cbSize = uint32(unsafe.Sizeof(info))
+	if ok, _, _ := procGetLastInputInfo.Call(uintptr(unsafe.Pointer(&info))); ok == 0 {
+		return 0
+	}
+	now, _, _ := procGetTickCount.Call()
+	return time.Duration(uint32(now)-info.dwTime) * time.Millisecond
+}
+
+// processDetails asks Windows for the full path of a process, which path rules need,
+// and when it started. Both are zero for processes we aren't allowed to open.
+func processDetails(pid uint32) (string, time.Time) {
+	h, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, pid)
+	if err != nil {
+		return "", time.Time{}
+	}
+	defer windows.CloseHandle(h)
+
+	var started time.Time
+	var creation, exit, kernel, user windows.Filetime
+	if windows.GetProcessTimes(h, &creation, &exit, &kernel, &user) == nil {
+		started = time.Unix(0, creation.Nanoseconds())
+	}
+	return imagePath(h), started
+}
+
+// processPath is processDetails without the start time, for callers that already have it.
+func processPath(pid uint32) string {
+	h, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, pid)
+	if err != nil {
+		return ""
+	}
+	defer windows.CloseHandle(h)
+	return imagePath(h)
+}
+
+func imagePath(h windows.Handle) string {
+	buf := make([]uint16, windows.MAX_LONG_PATH) // Games installed deep in long-path folders outgrow MAX_PATH
+	size := uint32(len(buf))
+	if err := windows.QueryFullProcessImageName(h, 0, &buf[0], &size); err != nil {
+		return ""
+	}
+	return windows.UTF16ToString(buf[:size])
+}
+
+// processNameFromTasklist is the fallback for elevated processes we can't open.
+func processNameFromTasklist(pid uint32) string {
+	// NH = No Header, FO CSV = Comma Separated for easy parsing
+	cmd := runSilentCommand("tasklist", "/FI", fmt.Sprintf("PID eq %d", pid), "/NH", "/FO", "CSV")
+	output, err := cmd.Output()
+	if err != nil || len(output) == 0 {
+		return ""
+	}
+
+	// tasklist CSV output looks like: "sentry.exe","1234","Console","1","5,000 K"
+	fields := strings.Split(string(output), ",")
+	return strings.Trim(fields[0], "\"")
+}
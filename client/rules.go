@@ -0,0 +1,118 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+
+	pb "github.com/Mohammad-Mahdi82/NexusOps/pkg/monitor"
+	"github.com/Mohammad-Mahdi82/NexusOps/pkg/processrules"
+)
+
+// processRules is the classification the server pushed over the stream.
+// Until the first push we ignore the same shells and launchers the server does by default.
+type processRules struct {
+	mu      sync.RWMutex
+	version int64
+	rules   []compiledRule
+}
+
+type compiledRule struct {
+	re     *regexp.Regexp
+	onPath bool
+	track  bool
+}
+
+var rules = newProcessRules()
+
+func newProcessRules() *processRules {
+	r := &processRules{}
+	for _, name := range processrules.DefaultIgnored {
+		r.rules = append(r.rules, compiledRule{re: globToRegexp(name)})
+	}
+	return r
+}
+
+// Version is reported in every heartbeat so the server knows when to resend.
+func (r *processRules) Version() int64 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.version
+}
+
+// Apply swaps in a new rule set. Rules that don't compile are skipped rather than
+// rejecting the whole set, so one typo on the server can't blind every PC.
+func (r *processRules) Apply(set *pb.ProcessRules) {
+	var compiled []compiledRule
+	for _, rule := range set.Rules {
+		c := compiledRule{
+			onPath: rule.Match != pb.ProcessRule_NAME_GLOB,
+			track:  rule.Action == pb.ProcessRule_TRACK,
+		}
+		if rule.Match == pb.ProcessRule_REGEX {
+			re, err := regexp.Compile("(?i)" + rule.Pattern)
+			if err != nil {
+				continue
+			}
+			c.re = re
+		} else {
+			c.re = globToRegexp(rule.Pattern)
+		}
+		compiled = append(compiled, c)
+	}
+
+	r.mu.Lock()
+	r.version = set.Version
+	r.rules = compiled
+	r.mu.Unlock()
+}
+
+// Ignored reports whether a process should be treated as "Idle" rather than a game.
+func (r *processRules) Ignored(name, path string) bool {
+	if name == "" {
+		return true
+	}
+	rule := r.match(name, path)
+	return rule != nil && !rule.track
+}
+
+// Tracked reports whether a rule explicitly names this process as a game, which is
+// what makes a background process worth reporting.
+func (r *processRules) Tracked(name, path string) bool {
+	rule := r.match(name, path)
+	return rule != nil && rule.track
+}
+
+func (r *processRules) match(name, path string) *compiledRule {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for i, rule := range r.rules {
+		subject := name
+		if rule.onPath {
+			subject = path
+		}
+		if subject != "" && rule.re.MatchString(subject) {
+			return &r.rules[i]
+		}
+	}
+	return nil
+}
+
+// globToRegexp turns a case-insensitive glob into a regexp. '*' also crosses path
+// separators so "C:\Games\*" covers every subfolder.
+func globToRegexp(glob string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("(?i)^")
+	for _, c := range glob {
+		switch c {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// AppName is used for the lock file and the autostart entry
+const AppName = "NexusOpsSentry"
+
+// mutexFile is kept open for the life of the process; the kernel drops the lock on exit.
+var mutexFile *os.File
+
+// createMutex emulates the Windows named mutex with an flock on a file in the temp dir.
+func createMutex(name string) bool {
+	safe := strings.NewReplacer("\\", "_", "/", "_").Replace(name)
+	f, err := os.OpenFile(filepath.Join(os.TempDir(), safe+".lock"), os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return true // Can't tell, so don't refuse to run
+	}
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		f.Close()
+		return false
+	}
+	mutexFile = f
+	return true
+}
+
+func runSilentCommand(name string, arg ...string) *exec.Cmd {
+	return exec.Command(name, arg...)
+}
+
+// closeGame force-closes every process with this executable name.
+func closeGame(name string) {
+	for _, a := range (linuxProbe{}).Processes() {
+		if a.Name == name && int(a.PID) != os.Getpid() {
+			_ = syscall.Kill(int(a.PID), syscall.SIGKILL)
+		}
+	}
+}
+
+// showNotice uses the desktop's notification daemon.
+func showNotice(text string) {
+	_ = exec.Command("notify-send", "--urgency=critical", "NexusOps", text).Run()
+}
+
+// setupClientFirewall is a no-op: desktop Linux distributions don't block mDNS replies by default.
+func setupClientFirewall() {}
+
+func autostartPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "autostart", AppName+".desktop")
+}
+
+// setAutoStart registers the Sentry as an XDG autostart entry for the desktop session.
+func setAutoStart(configPath, serverAddr string) {
+	path := autostartPath()
+	if path == "" {
+		return
+	}
+	runCmd := autostartCommand(configPath, serverAddr)
+	entry := fmt.Sprintf("[Desktop Entry]\nType=Application\nName=%s\nExec=%s\nNoDisplay=true\nX-GNOME-Autostart-enabled=true\n",
+		AppName, runCmd)
+	_ = os.MkdirAll(filepath.Dir(path), 0o755)
+	_ = os.WriteFile(path, []byte(entry), 0o644)
+}
+
+func handleUninstall() {
+	if path := autostartPath(); path != "" {
+		_ = os.Remove(path)
+	}
+
+	exePath, _ := os.Executable()
+	fmt.Printf("Stopping %s and removing from startup...\n", filepath.Base(exePath))
+	closeGame(filepath.Base(exePath))
+	os.Exit(0)
+}
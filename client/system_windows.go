@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath" // Use this for safer path handling
+	"sync/atomic"
 	"syscall"
 	"unsafe"
 )
@@ -16,8 +17,15 @@ const AppName = "NexusOpsSentry"
 var (
 	kernel32        = syscall.NewLazyDLL("kernel32.dll")
 	procCreateMutex = kernel32.NewProc("CreateMutexW")
+	procMessageBox  = user32.NewProc("MessageBoxW")
 )
 
+// MB_OK | MB_ICONWARNING | MB_SETFOREGROUND | MB_TOPMOST
+const noticeBoxStyle = 0x00000000 | 0x00000030 | 0x00010000 | 0x00040000
+
+// noticeOpen keeps a second notice from stacking on top of one nobody has dismissed yet.
+var noticeOpen atomic.Bool
+
 func createMutex(name string) bool {
 	lpName, _ := syscall.UTF16PtrFromString(name)
 	_, _, err := procCreateMutex.Call(0, 1, uintptr(unsafe.Pointer(lpName)))
@@ -30,10 +38,34 @@ func runSilentCommand(name string, arg ...string) *exec.Cmd {
 	return cmd
 }
 
-func setAutoStart(serverAddr string) {
-	exePath, _ := os.Executable()
-	// Adding quotes around exePath is vital in case the user's name has a space
-	runCmd := fmt.Sprintf("\"%s\" -server %s", exePath, serverAddr)
+// closeGame force-closes every process with this image name.
+func closeGame(name string) {
+	_ = runSilentCommand("taskkill", "/F", "/IM", name).Run()
+}
+
+// showNotice pops a top-most message box over whatever the customer is doing. Blocks until dismissed.
+func showNotice(text string) {
+	if !noticeOpen.CompareAndSwap(false, true) {
+		return
+	}
+	defer noticeOpen.Store(false)
+
+	msg, _ := syscall.UTF16PtrFromString(text)
+	title, _ := syscall.UTF16PtrFromString("NexusOps")
+	procMessageBox.Call(0, uintptr(unsafe.Pointer(msg)), uintptr(unsafe.Pointer(title)), noticeBoxStyle)
+}
+
+func setupClientFirewall() {
+	// 1. Wipe
+	runSilentCommand("netsh", "advfirewall", "firewall", "delete", "rule", "name=NexusOps_Client_Discovery").Run()
+
+	// 2. Apply
+	runSilentCommand("netsh", "advfirewall", "firewall", "add", "rule",
+		"name=NexusOps_Client_Discovery", "dir=in", "action=allow", "protocol=UDP", "localport=5353", "profile=any").Run()
+}
+
+func setAutoStart(configPath, serverAddr string) {
+	runCmd := autostartCommand(configPath, serverAddr)
 	key, _, _ := registry.CreateKey(registry.CURRENT_USER, `Software\Microsoft\Windows\CurrentVersion\Run`, registry.SET_VALUE)
 	defer key.Close()
 	_ = key.SetStringValue(AppName, runCmd)
@@ -0,0 +1,836 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v4.24.4
+// source: admin.proto
+
+package monitor
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	AdminService_GetConsoleConfig_FullMethodName  = "/monitor.AdminService/GetConsoleConfig"
+	AdminService_WatchDashboard_FullMethodName    = "/monitor.AdminService/WatchDashboard"
+	AdminService_WatchEvents_FullMethodName       = "/monitor.AdminService/WatchEvents"
+	AdminService_WatchLogs_FullMethodName         = "/monitor.AdminService/WatchLogs"
+	AdminService_RecordPayment_FullMethodName     = "/monitor.AdminService/RecordPayment"
+	AdminService_SetStationOpen_FullMethodName    = "/monitor.AdminService/SetStationOpen"
+	AdminService_StartTimedSession_FullMethodName = "/monitor.AdminService/StartTimedSession"
+	AdminService_SetIdlePolicy_FullMethodName     = "/monitor.AdminService/SetIdlePolicy"
+	AdminService_ListPendingGames_FullMethodName  = "/monitor.AdminService/ListPendingGames"
+	AdminService_ClassifyGame_FullMethodName      = "/monitor.AdminService/ClassifyGame"
+	AdminService_ListStations_FullMethodName      = "/monitor.AdminService/ListStations"
+	AdminService_GetStation_FullMethodName        = "/monitor.AdminService/GetStation"
+	AdminService_ListSessions_FullMethodName      = "/monitor.AdminService/ListSessions"
+	AdminService_GetSession_FullMethodName        = "/monitor.AdminService/GetSession"
+	AdminService_ListPayments_FullMethodName      = "/monitor.AdminService/ListPayments"
+	AdminService_UpdateSession_FullMethodName     = "/monitor.AdminService/UpdateSession"
+	AdminService_SendCommand_FullMethodName       = "/monitor.AdminService/SendCommand"
+	AdminService_GetReport_FullMethodName         = "/monitor.AdminService/GetReport"
+)
+
+// AdminServiceClient is the client API for AdminService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AdminServiceClient interface {
+	// Settings the console needs to render: locale, payment methods, rate classes.
+	GetConsoleConfig(ctx context.Context, in *ConsoleConfigRequest, opts ...grpc.CallOption) (*ConsoleConfig, error)
+	// Sends the current dashboard, then a fresh one whenever something changes.
+	WatchDashboard(ctx context.Context, in *WatchDashboardRequest, opts ...grpc.CallOption) (AdminService_WatchDashboardClient, error)
+	// Sends a SNAPSHOT, then one event per change. A watcher that falls too far behind
+	// is disconnected and should watch again to get a fresh snapshot.
+	WatchEvents(ctx context.Context, in *WatchEventsRequest, opts ...grpc.CallOption) (AdminService_WatchEventsClient, error)
+	// Sends recent log lines, then new ones as the daemon writes them.
+	WatchLogs(ctx context.Context, in *WatchLogsRequest, opts ...grpc.CallOption) (AdminService_WatchLogsClient, error)
+	RecordPayment(ctx context.Context, in *RecordPaymentRequest, opts ...grpc.CallOption) (*RecordPaymentResponse, error)
+	SetStationOpen(ctx context.Context, in *SetStationOpenRequest, opts ...grpc.CallOption) (*Station, error)
+	StartTimedSession(ctx context.Context, in *StartTimedSessionRequest, opts ...grpc.CallOption) (*Station, error)
+	SetIdlePolicy(ctx context.Context, in *SetIdlePolicyRequest, opts ...grpc.CallOption) (*Station, error)
+	ListPendingGames(ctx context.Context, in *ListPendingGamesRequest, opts ...grpc.CallOption) (*ListPendingGamesResponse, error)
+	ClassifyGame(ctx context.Context, in *ClassifyGameRequest, opts ...grpc.CallOption) (*Game, error)
+	// Every PC the server knows about, connected or not.
+	ListStations(ctx context.Context, in *ListStationsRequest, opts ...grpc.CallOption) (*ListStationsResponse, error)
+	GetStation(ctx context.Context, in *GetStationRequest, opts ...grpc.CallOption) (*Station, error)
+	ListSessions(ctx context.Context, in *ListSessionsRequest, opts ...grpc.CallOption) (*ListSessionsResponse, error)
+	GetSession(ctx context.Context, in *GetSessionRequest, opts ...grpc.CallOption) (*Session, error)
+	ListPayments(ctx context.Context, in *ListPaymentsRequest, opts ...grpc.CallOption) (*ListPaymentsResponse, error)
+	// Corrects an ended, unpaid session before it is settled.
+	UpdateSession(ctx context.Context, in *UpdateSessionRequest, opts ...grpc.CallOption) (*Session, error)
+	SendCommand(ctx context.Context, in *SendCommandRequest, opts ...grpc.CallOption) (*SendCommandResponse, error)
+	// Revenue and utilization over a date range, grouped by period, PC, game and operator.
+	GetReport(ctx context.Context, in *ReportRequest, opts ...grpc.CallOption) (*Report, error)
+}
+
+type adminServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdminServiceClient(cc grpc.ClientConnInterface) AdminServiceClient {
+	return &adminServiceClient{cc}
+}
+
+func (c *adminServiceClient) GetConsoleConfig(ctx context.Context, in *ConsoleConfigRequest, opts ...grpc.CallOption) (*ConsoleConfig, error) {
+	out := new(ConsoleConfig)
+	err := c.cc.Invoke(ctx, AdminService_GetConsoleConfig_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) WatchDashboard(ctx context.Context, in *WatchDashboardRequest, opts ...grpc.CallOption) (AdminService_WatchDashboardClient, error) {
+	stream, err := c.cc.NewStream(ctx, &AdminService_ServiceDesc.Streams[0], AdminService_WatchDashboard_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &adminServiceWatchDashboardClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type AdminService_WatchDashboardClient interface {
+	Recv() (*Dashboard, error)
+	grpc.ClientStream
+}
+
+type adminServiceWatchDashboardClient struct {
+	grpc.ClientStream
+}
+
+func (x *adminServiceWatchDashboardClient) Recv() (*Dashboard, error) {
+	m := new(Dashboard)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *adminServiceClient) WatchEvents(ctx context.Context, in *WatchEventsRequest, opts ...grpc.CallOption) (AdminService_WatchEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &AdminService_ServiceDesc.Streams[1], AdminService_WatchEvents_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &adminServiceWatchEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type AdminService_WatchEventsClient interface {
+	Recv() (*Event, error)
+	grpc.ClientStream
+}
+
+type adminServiceWatchEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *adminServiceWatchEventsClient) Recv() (*Event, error) {
+	m := new(Event)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *adminServiceClient) WatchLogs(ctx context.Context, in *WatchLogsRequest, opts ...grpc.CallOption) (AdminService_WatchLogsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &AdminService_ServiceDesc.Streams[2], AdminService_WatchLogs_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &adminServiceWatchLogsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type AdminService_WatchLogsClient interface {
+	Recv() (*LogEntry, error)
+	grpc.ClientStream
+}
+
+type adminServiceWatchLogsClient struct {
+	grpc.ClientStream
+}
+
+func (x *adminServiceWatchLogsClient) Recv() (*LogEntry, error) {
+	m := new(LogEntry)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *adminServiceClient) RecordPayment(ctx context.Context, in *RecordPaymentRequest, opts ...grpc.CallOption) (*RecordPaymentResponse, error) {
+	out := new(RecordPaymentResponse)
+	err := c.cc.Invoke(ctx, AdminService_RecordPayment_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) SetStationOpen(ctx context.Context, in *SetStationOpenRequest, opts ...grpc.CallOption) (*Station, error) {
+	out := new(Station)
+	err := c.cc.Invoke(ctx, AdminService_SetStationOpen_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) StartTimedSession(ctx context.Context, in *StartTimedSessionRequest, opts ...grpc.CallOption) (*Station, error) {
+	out := new(Station)
+	err := c.cc.Invoke(ctx, AdminService_StartTimedSession_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) SetIdlePolicy(ctx context.Context, in *SetIdlePolicyRequest, opts ...grpc.CallOption) (*Station, error) {
+	out := new(Station)
+	err := c.cc.Invoke(ctx, AdminService_SetIdlePolicy_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ListPendingGames(ctx context.Context, in *ListPendingGamesRequest, opts ...grpc.CallOption) (*ListPendingGamesResponse, error) {
+	out := new(ListPendingGamesResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListPendingGames_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ClassifyGame(ctx context.Context, in *ClassifyGameRequest, opts ...grpc.CallOption) (*Game, error) {
+	out := new(Game)
+	err := c.cc.Invoke(ctx, AdminService_ClassifyGame_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ListStations(ctx context.Context, in *ListStationsRequest, opts ...grpc.CallOption) (*ListStationsResponse, error) {
+	out := new(ListStationsResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListStations_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) GetStation(ctx context.Context, in *GetStationRequest, opts ...grpc.CallOption) (*Station, error) {
+	out := new(Station)
+	err := c.cc.Invoke(ctx, AdminService_GetStation_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ListSessions(ctx context.Context, in *ListSessionsRequest, opts ...grpc.CallOption) (*ListSessionsResponse, error) {
+	out := new(ListSessionsResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListSessions_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) GetSession(ctx context.Context, in *GetSessionRequest, opts ...grpc.CallOption) (*Session, error) {
+	out := new(Session)
+	err := c.cc.Invoke(ctx, AdminService_GetSession_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ListPayments(ctx context.Context, in *ListPaymentsRequest, opts ...grpc.CallOption) (*ListPaymentsResponse, error) {
+	out := new(ListPaymentsResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListPayments_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) UpdateSession(ctx context.Context, in *UpdateSessionRequest, opts ...grpc.CallOption) (*Session, error) {
+	out := new(Session)
+	err := c.cc.Invoke(ctx, AdminService_UpdateSession_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) SendCommand(ctx context.Context, in *SendCommandRequest, opts ...grpc.CallOption) (*SendCommandResponse, error) {
+	out := new(SendCommandResponse)
+	err := c.cc.Invoke(ctx, AdminService_SendCommand_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) GetReport(ctx context.Context, in *ReportRequest, opts ...grpc.CallOption) (*Report, error) {
+	out := new(Report)
+	err := c.cc.Invoke(ctx, AdminService_GetReport_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminServiceServer is the server API for AdminService service.
+// All implementations must embed UnimplementedAdminServiceServer
+// for forward compatibility
+type AdminServiceServer interface {
+	// Settings the console needs to render: locale, payment methods, rate classes.
+	GetConsoleConfig(context.Context, *ConsoleConfigRequest) (*ConsoleConfig, error)
+	// Sends the current dashboard, then a fresh one whenever something changes.
+	WatchDashboard(*WatchDashboardRequest, AdminService_WatchDashboardServer) error
+	// Sends a SNAPSHOT, then one event per change. A watcher that falls too far behind
+	// is disconnected and should watch again to get a fresh snapshot.
+	WatchEvents(*WatchEventsRequest, AdminService_WatchEventsServer) error
+	// Sends recent log lines, then new ones as the daemon writes them.
+	WatchLogs(*WatchLogsRequest, AdminService_WatchLogsServer) error
+	RecordPayment(context.Context, *RecordPaymentRequest) (*RecordPaymentResponse, error)
+	SetStationOpen(context.Context, *SetStationOpenRequest) (*Station, error)
+	StartTimedSession(context.Context, *StartTimedSessionRequest) (*Station, error)
+	SetIdlePolicy(context.Context, *SetIdlePolicyRequest) (*Station, error)
+	ListPendingGames(context.Context, *ListPendingGamesRequest) (*ListPendingGamesResponse, error)
+	ClassifyGame(context.Context, *ClassifyGameRequest) (*Game, error)
+	// Every PC the server knows about, connected or not.
+	ListStations(context.Context, *ListStationsRequest) (*ListStationsResponse, error)
+	GetStation(context.Context, *GetStationRequest) (*Station, error)
+	ListSessions(context.Context, *ListSessionsRequest) (*ListSessionsResponse, error)
+	GetSession(context.Context, *GetSessionRequest) (*Session, error)
+	ListPayments(context.Context, *ListPaymentsRequest) (*ListPaymentsResponse, error)
+	// Corrects an ended, unpaid session before it is settled.
+	UpdateSession(context.Context, *UpdateSessionRequest) (*Session, error)
+	SendCommand(context.Context, *SendCommandRequest) (*SendCommandResponse, error)
+	// Revenue and utilization over a date range, grouped by period, PC, game and operator.
+	GetReport(context.Context, *ReportRequest) (*Report, error)
+	mustEmbedUnimplementedAdminServiceServer()
+}
+
+// UnimplementedAdminServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedAdminServiceServer struct {
+}
+
+func (UnimplementedAdminServiceServer) GetConsoleConfig(context.Context, *ConsoleConfigRequest) (*ConsoleConfig, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetConsoleConfig not implemented")
+}
+func (UnimplementedAdminServiceServer) WatchDashboard(*WatchDashboardRequest, AdminService_WatchDashboardServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchDashboard not implemented")
+}
+func (UnimplementedAdminServiceServer) WatchEvents(*WatchEventsRequest, AdminService_WatchEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchEvents not implemented")
+}
+func (UnimplementedAdminServiceServer) WatchLogs(*WatchLogsRequest, AdminService_WatchLogsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchLogs not implemented")
+}
+func (UnimplementedAdminServiceServer) RecordPayment(context.Context, *RecordPaymentRequest) (*RecordPaymentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RecordPayment not implemented")
+}
+func (UnimplementedAdminServiceServer) SetStationOpen(context.Context, *SetStationOpenRequest) (*Station, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetStationOpen not implemented")
+}
+func (UnimplementedAdminServiceServer) StartTimedSession(context.Context, *StartTimedSessionRequest) (*Station, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartTimedSession not implemented")
+}
+func (UnimplementedAdminServiceServer) SetIdlePolicy(context.Context, *SetIdlePolicyRequest) (*Station, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetIdlePolicy not implemented")
+}
+func (UnimplementedAdminServiceServer) ListPendingGames(context.Context, *ListPendingGamesRequest) (*ListPendingGamesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPendingGames not implemented")
+}
+func (UnimplementedAdminServiceServer) ClassifyGame(context.Context, *ClassifyGameRequest) (*Game, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ClassifyGame not implemented")
+}
+func (UnimplementedAdminServiceServer) ListStations(context.Context, *ListStationsRequest) (*ListStationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListStations not implemented")
+}
+func (UnimplementedAdminServiceServer) GetStation(context.Context, *GetStationRequest) (*Station, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStation not implemented")
+}
+func (UnimplementedAdminServiceServer) ListSessions(context.Context, *ListSessionsRequest) (*ListSessionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSessions not implemented")
+}
+func (UnimplementedAdminServiceServer) GetSession(context.Context, *GetSessionRequest) (*Session, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSession not implemented")
+}
+func (UnimplementedAdminServiceServer) ListPayments(context.Context, *ListPaymentsRequest) (*ListPaymentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPayments not implemented")
+}
+func (UnimplementedAdminServiceServer) UpdateSession(context.Context, *UpdateSessionRequest) (*Session, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateSession not implemented")
+}
+func (UnimplementedAdminServiceServer) SendCommand(context.Context, *SendCommandRequest) (*SendCommandResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendCommand not implemented")
+}
+func (UnimplementedAdminServiceServer) GetReport(context.Context, *ReportRequest) (*Report, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetReport not implemented")
+}
+func (UnimplementedAdminServiceServer) mustEmbedUnimplementedAdminServiceServer() {}
+
+// UnsafeAdminServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AdminServiceServer will
+// result in compilation errors.
+type UnsafeAdminServiceServer interface {
+	mustEmbedUnimplementedAdminServiceServer()
+}
+
+func RegisterAdminServiceServer(s grpc.ServiceRegistrar, srv AdminServiceServer) {
+	s.RegisterService(&AdminService_ServiceDesc, srv)
+}
+
+func _AdminService_GetConsoleConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConsoleConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetConsoleConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetConsoleConfig_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetConsoleConfig(ctx, req.(*ConsoleConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_WatchDashboard_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchDashboardRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AdminServiceServer).WatchDashboard(m, &adminServiceWatchDashboardServer{stream})
+}
+
+type AdminService_WatchDashboardServer interface {
+	Send(*Dashboard) error
+	grpc.ServerStream
+}
+
+type adminServiceWatchDashboardServer struct {
+	grpc.ServerStream
+}
+
+func (x *adminServiceWatchDashboardServer) Send(m *Dashboard) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _AdminService_WatchEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AdminServiceServer).WatchEvents(m, &adminServiceWatchEventsServer{stream})
+}
+
+type AdminService_WatchEventsServer interface {
+	Send(*Event) error
+	grpc.ServerStream
+}
+
+type adminServiceWatchEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *adminServiceWatchEventsServer) Send(m *Event) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _AdminService_WatchLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchLogsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AdminServiceServer).WatchLogs(m, &adminServiceWatchLogsServer{stream})
+}
+
+type AdminService_WatchLogsServer interface {
+	Send(*LogEntry) error
+	grpc.ServerStream
+}
+
+type adminServiceWatchLogsServer struct {
+	grpc.ServerStream
+}
+
+func (x *adminServiceWatchLogsServer) Send(m *LogEntry) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _AdminService_RecordPayment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RecordPaymentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).RecordPayment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_RecordPayment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).RecordPayment(ctx, req.(*RecordPaymentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_SetStationOpen_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetStationOpenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).SetStationOpen(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_SetStationOpen_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).SetStationOpen(ctx, req.(*SetStationOpenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_StartTimedSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartTimedSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).StartTimedSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_StartTimedSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).StartTimedSession(ctx, req.(*StartTimedSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_SetIdlePolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetIdlePolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).SetIdlePolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_SetIdlePolicy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).SetIdlePolicy(ctx, req.(*SetIdlePolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ListPendingGames_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPendingGamesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListPendingGames(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListPendingGames_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListPendingGames(ctx, req.(*ListPendingGamesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ClassifyGame_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClassifyGameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ClassifyGame(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ClassifyGame_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ClassifyGame(ctx, req.(*ClassifyGameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ListStations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListStationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListStations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListStations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListStations(ctx, req.(*ListStationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_GetStation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetStation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetStation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetStation(ctx, req.(*GetStationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ListSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSessionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListSessions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListSessions(ctx, req.(*ListSessionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_GetSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetSession(ctx, req.(*GetSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ListPayments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPaymentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListPayments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListPayments_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListPayments(ctx, req.(*ListPaymentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_UpdateSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).UpdateSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_UpdateSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).UpdateSession(ctx, req.(*UpdateSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_SendCommand_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendCommandRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).SendCommand(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_SendCommand_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).SendCommand(ctx, req.(*SendCommandRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_GetReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetReport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetReport(ctx, req.(*ReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AdminService_ServiceDesc is the grpc.ServiceDesc for AdminService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AdminService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "monitor.AdminService",
+	HandlerType: (*AdminServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetConsoleConfig",
+			Handler:    _AdminService_GetConsoleConfig_Handler,
+		},
+		{
+			MethodName: "RecordPayment",
+			Handler:    _AdminService_RecordPayment_Handler,
+		},
+		{
+			MethodName: "SetStationOpen",
+			Handler:    _AdminService_SetStationOpen_Handler,
+		},
+		{
+			MethodName: "StartTimedSession",
+			Handler:    _AdminService_StartTimedSession_Handler,
+		},
+		{
+			MethodName: "SetIdlePolicy",
+			Handler:    _AdminService_SetIdlePolicy_Handler,
+		},
+		{
+			MethodName: "ListPendingGames",
+			Handler:    _AdminService_ListPendingGames_Handler,
+		},
+		{
+			MethodName: "ClassifyGame",
+			Handler:    _AdminService_ClassifyGame_Handler,
+		},
+		{
+			MethodName: "ListStations",
+			Handler:    _AdminService_ListStations_Handler,
+		},
+		{
+			MethodName: "GetStation",
+			Handler:    _AdminService_GetStation_Handler,
+		},
+		{
+			MethodName: "ListSessions",
+			Handler:    _AdminService_ListSessions_Handler,
+		},
+		{
+			MethodName: "GetSession",
+			Handler:    _AdminService_GetSession_Handler,
+		},
+		{
+			MethodName: "ListPayments",
+			Handler:    _AdminService_ListPayments_Handler,
+		},
+		{
+			MethodName: "UpdateSession",
+			Handler:    _AdminService_UpdateSession_Handler,
+		},
+		{
+			MethodName: "SendCommand",
+			Handler:    _AdminService_SendCommand_Handler,
+		},
+		{
+			MethodName: "GetReport",
+			Handler:    _AdminService_GetReport_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchDashboard",
+			Handler:       _AdminService_WatchDashboard_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchEvents",
+			Handler:       _AdminService_WatchEvents_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchLogs",
+			Handler:       _AdminService_WatchLogs_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "admin.proto",
+}
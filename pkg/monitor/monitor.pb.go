@@ -20,14 +20,168 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type CloseResult_Outcome int32
+
+const (
+	CloseResult_CLOSED      CloseResult_Outcome = 0 // Exited on its own after the polite request
+	CloseResult_KILLED      CloseResult_Outcome = 1 // Had to be forced after the timeout
+	CloseResult_FAILED      CloseResult_Outcome = 2 // Still running after being forced
+	CloseResult_NOT_RUNNING CloseResult_Outcome = 3 // Already gone, or the PID now belongs to something else
+)
+
+// Enum value maps for CloseResult_Outcome.
+var (
+	CloseResult_Outcome_name = map[int32]string{
+		0: "CLOSED",
+		1: "KILLED",
+		2: "FAILED",
+		3: "NOT_RUNNING",
+	}
+	CloseResult_Outcome_value = map[string]int32{
+		"CLOSED":      0,
+		"KILLED":      1,
+		"FAILED":      2,
+		"NOT_RUNNING": 3,
+	}
+)
+
+func (x CloseResult_Outcome) Enum() *CloseResult_Outcome {
+	p := new(CloseResult_Outcome)
+	*p = x
+	return p
+}
+
+func (x CloseResult_Outcome) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CloseResult_Outcome) Descriptor() protoreflect.EnumDescriptor {
+	return file_monitor_proto_enumTypes[0].Descriptor()
+}
+
+func (CloseResult_Outcome) Type() protoreflect.EnumType {
+	return &file_monitor_proto_enumTypes[0]
+}
+
+func (x CloseResult_Outcome) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CloseResult_Outcome.Descriptor instead.
+func (CloseResult_Outcome) EnumDescriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{4, 0}
+}
+
+type ProcessRule_Match int32
+
+const (
+	ProcessRule_NAME_GLOB ProcessRule_Match = 0 // Glob against the image name, e.g. "steam*.exe"
+	ProcessRule_PATH_GLOB ProcessRule_Match = 1 // Glob against the full image path, e.g. "C:\Windows\*"
+	ProcessRule_REGEX     ProcessRule_Match = 2 // Regular expression against the full image path
+)
+
+// Enum value maps for ProcessRule_Match.
+var (
+	ProcessRule_Match_name = map[int32]string{
+		0: "NAME_GLOB",
+		1: "PATH_GLOB",
+		2: "REGEX",
+	}
+	ProcessRule_Match_value = map[string]int32{
+		"NAME_GLOB": 0,
+		"PATH_GLOB": 1,
+		"REGEX":     2,
+	}
+)
+
+func (x ProcessRule_Match) Enum() *ProcessRule_Match {
+	p := new(ProcessRule_Match)
+	*p = x
+	return p
+}
+
+func (x ProcessRule_Match) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ProcessRule_Match) Descriptor() protoreflect.EnumDescriptor {
+	return file_monitor_proto_enumTypes[1].Descriptor()
+}
+
+func (ProcessRule_Match) Type() protoreflect.EnumType {
+	return &file_monitor_proto_enumTypes[1]
+}
+
+func (x ProcessRule_Match) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ProcessRule_Match.Descriptor instead.
+func (ProcessRule_Match) EnumDescriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{6, 0}
+}
+
+type ProcessRule_Action int32
+
+const (
+	ProcessRule_IGNORE ProcessRule_Action = 0 // Never a game (launchers, shells, system tasks)
+	ProcessRule_TRACK  ProcessRule_Action = 1 // Always a game, e.g. to carve a title out of an ignored folder
+)
+
+// Enum value maps for ProcessRule_Action.
+var (
+	ProcessRule_Action_name = map[int32]string{
+		0: "IGNORE",
+		1: "TRACK",
+	}
+	ProcessRule_Action_value = map[string]int32{
+		"IGNORE": 0,
+		"TRACK":  1,
+	}
+)
+
+func (x ProcessRule_Action) Enum() *ProcessRule_Action {
+	p := new(ProcessRule_Action)
+	*p = x
+	return p
+}
+
+func (x ProcessRule_Action) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ProcessRule_Action) Descriptor() protoreflect.EnumDescriptor {
+	return file_monitor_proto_enumTypes[2].Descriptor()
+}
+
+func (ProcessRule_Action) Type() protoreflect.EnumType {
+	return &file_monitor_proto_enumTypes[2]
+}
+
+func (x ProcessRule_Action) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ProcessRule_Action.Descriptor instead.
+func (ProcessRule_Action) EnumDescriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{6, 1}
+}
+
+// Heartbeat is sent when something the server cares about changes, and at least
+// every keepalive interval otherwise.
 type Heartbeat struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	PcId        string `protobuf:"bytes,1,opt,name=pc_id,json=pcId,proto3" json:"pc_id,omitempty"`
-	CurrentGame string `protobuf:"bytes,2,opt,name=current_game,json=currentGame,proto3" json:"current_game,omitempty"`
-	Timestamp   int64  `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	PcId            string         `protobuf:"bytes,1,opt,name=pc_id,json=pcId,proto3" json:"pc_id,omitempty"`
+	CurrentGame     string         `protobuf:"bytes,2,opt,name=current_game,json=currentGame,proto3" json:"current_game,omitempty"`
+	Timestamp       int64          `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	RulesVersion    int64          `protobuf:"varint,4,opt,name=rules_version,json=rulesVersion,proto3" json:"rules_version,omitempty"`          // Version of the ProcessRules the client is applying
+	Games           []*GameProcess `protobuf:"bytes,5,rep,name=games,proto3" json:"games,omitempty"`                                             // Every running game, focused or not
+	CloseResults    []*CloseResult `protobuf:"bytes,6,rep,name=close_results,json=closeResults,proto3" json:"close_results,omitempty"`           // Outcomes of close requests since the last heartbeat
+	IdleSeconds     uint32         `protobuf:"varint,7,opt,name=idle_seconds,json=idleSeconds,proto3" json:"idle_seconds,omitempty"`             // Time since the last keyboard or mouse input
+	SettingsVersion int64          `protobuf:"varint,8,opt,name=settings_version,json=settingsVersion,proto3" json:"settings_version,omitempty"` // Version of the ClientSettings the client is applying
 }
 
 func (x *Heartbeat) Reset() {
@@ -83,18 +237,149 @@ func (x *Heartbeat) GetTimestamp() int64 {
 	return 0
 }
 
+func (x *Heartbeat) GetRulesVersion() int64 {
+	if x != nil {
+		return x.RulesVersion
+	}
+	return 0
+}
+
+func (x *Heartbeat) GetGames() []*GameProcess {
+	if x != nil {
+		return x.Games
+	}
+	return nil
+}
+
+func (x *Heartbeat) GetCloseResults() []*CloseResult {
+	if x != nil {
+		return x.CloseResults
+	}
+	return nil
+}
+
+func (x *Heartbeat) GetIdleSeconds() uint32 {
+	if x != nil {
+		return x.IdleSeconds
+	}
+	return 0
+}
+
+func (x *Heartbeat) GetSettingsVersion() int64 {
+	if x != nil {
+		return x.SettingsVersion
+	}
+	return 0
+}
+
+// GameProcess is a running process the rules recognize as a game, or the focused
+// process when no rule ignores it.
+type GameProcess struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pid       uint32 `protobuf:"varint,1,opt,name=pid,proto3" json:"pid,omitempty"`
+	ParentPid uint32 `protobuf:"varint,2,opt,name=parent_pid,json=parentPid,proto3" json:"parent_pid,omitempty"`
+	Name      string `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Path      string `protobuf:"bytes,4,opt,name=path,proto3" json:"path,omitempty"`
+	StartTime int64  `protobuf:"varint,5,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"` // Unix seconds
+	Focused   bool   `protobuf:"varint,6,opt,name=focused,proto3" json:"focused,omitempty"`
+}
+
+func (x *GameProcess) Reset() {
+	*x = GameProcess{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_monitor_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GameProcess) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GameProcess) ProtoMessage() {}
+
+func (x *GameProcess) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GameProcess.ProtoReflect.Descriptor instead.
+func (*GameProcess) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GameProcess) GetPid() uint32 {
+	if x != nil {
+		return x.Pid
+	}
+	return 0
+}
+
+func (x *GameProcess) GetParentPid() uint32 {
+	if x != nil {
+		return x.ParentPid
+	}
+	return 0
+}
+
+func (x *GameProcess) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *GameProcess) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *GameProcess) GetStartTime() int64 {
+	if x != nil {
+		return x.StartTime
+	}
+	return 0
+}
+
+func (x *GameProcess) GetFocused() bool {
+	if x != nil {
+		return x.Focused
+	}
+	return false
+}
+
 type CommandResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	CloseActiveGame bool `protobuf:"varint,1,opt,name=close_active_game,json=closeActiveGame,proto3" json:"close_active_game,omitempty"`
+	CloseActiveGame     bool            `protobuf:"varint,1,opt,name=close_active_game,json=closeActiveGame,proto3" json:"close_active_game,omitempty"`             // Legacy: force-close the focused game. Ignored when close_games is set
+	ProcessRules        *ProcessRules   `protobuf:"bytes,2,opt,name=process_rules,json=processRules,proto3" json:"process_rules,omitempty"`                         // Only sent when the client's rules_version is stale
+	CloseGames          []*GameProcess  `protobuf:"bytes,3,rep,name=close_games,json=closeGames,proto3" json:"close_games,omitempty"`                               // Close these processes and their children
+	CloseTimeoutSeconds int32           `protobuf:"varint,4,opt,name=close_timeout_seconds,json=closeTimeoutSeconds,proto3" json:"close_timeout_seconds,omitempty"` // How long a game gets to exit on its own before it's killed
+	Notice              string          `protobuf:"bytes,5,opt,name=notice,proto3" json:"notice,omitempty"`                                                         // Message to show the customer, e.g. an idle warning
+	Locked              bool            `protobuf:"varint,6,opt,name=locked,proto3" json:"locked,omitempty"`                                                        // Desired lock screen state, sent with every response
+	LockMessage         string          `protobuf:"bytes,7,opt,name=lock_message,json=lockMessage,proto3" json:"lock_message,omitempty"`                            // Shown on the lock screen
+	Settings            *ClientSettings `protobuf:"bytes,8,opt,name=settings,proto3" json:"settings,omitempty"`                                                     // Only sent when the client's settings_version is stale
 }
 
 func (x *CommandResponse) Reset() {
 	*x = CommandResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_monitor_proto_msgTypes[1]
+		mi := &file_monitor_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -107,7 +392,7 @@ func (x *CommandResponse) String() string {
 func (*CommandResponse) ProtoMessage() {}
 
 func (x *CommandResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_monitor_proto_msgTypes[1]
+	mi := &file_monitor_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -120,7 +405,7 @@ func (x *CommandResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CommandResponse.ProtoReflect.Descriptor instead.
 func (*CommandResponse) Descriptor() ([]byte, []int) {
-	return file_monitor_proto_rawDescGZIP(), []int{1}
+	return file_monitor_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *CommandResponse) GetCloseActiveGame() bool {
@@ -130,30 +415,431 @@ func (x *CommandResponse) GetCloseActiveGame() bool {
 	return false
 }
 
+func (x *CommandResponse) GetProcessRules() *ProcessRules {
+	if x != nil {
+		return x.ProcessRules
+	}
+	return nil
+}
+
+func (x *CommandResponse) GetCloseGames() []*GameProcess {
+	if x != nil {
+		return x.CloseGames
+	}
+	return nil
+}
+
+func (x *CommandResponse) GetCloseTimeoutSeconds() int32 {
+	if x != nil {
+		return x.CloseTimeoutSeconds
+	}
+	return 0
+}
+
+func (x *CommandResponse) GetNotice() string {
+	if x != nil {
+		return x.Notice
+	}
+	return ""
+}
+
+func (x *CommandResponse) GetLocked() bool {
+	if x != nil {
+		return x.Locked
+	}
+	return false
+}
+
+func (x *CommandResponse) GetLockMessage() string {
+	if x != nil {
+		return x.LockMessage
+	}
+	return ""
+}
+
+func (x *CommandResponse) GetSettings() *ClientSettings {
+	if x != nil {
+		return x.Settings
+	}
+	return nil
+}
+
+// ClientSettings override the Sentry's own config file fleet-wide.
+// A zero value leaves the client's local setting alone.
+type ClientSettings struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version                  int64  `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	HeartbeatIntervalSeconds uint32 `protobuf:"varint,2,opt,name=heartbeat_interval_seconds,json=heartbeatIntervalSeconds,proto3" json:"heartbeat_interval_seconds,omitempty"`
+	DiscoveryTimeoutSeconds  uint32 `protobuf:"varint,3,opt,name=discovery_timeout_seconds,json=discoveryTimeoutSeconds,proto3" json:"discovery_timeout_seconds,omitempty"`
+	KeepaliveSeconds         uint32 `protobuf:"varint,4,opt,name=keepalive_seconds,json=keepaliveSeconds,proto3" json:"keepalive_seconds,omitempty"` // Longest gap between heartbeats when nothing changes
+}
+
+func (x *ClientSettings) Reset() {
+	*x = ClientSettings{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_monitor_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ClientSettings) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClientSettings) ProtoMessage() {}
+
+func (x *ClientSettings) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClientSettings.ProtoReflect.Descriptor instead.
+func (*ClientSettings) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ClientSettings) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *ClientSettings) GetHeartbeatIntervalSeconds() uint32 {
+	if x != nil {
+		return x.HeartbeatIntervalSeconds
+	}
+	return 0
+}
+
+func (x *ClientSettings) GetDiscoveryTimeoutSeconds() uint32 {
+	if x != nil {
+		return x.DiscoveryTimeoutSeconds
+	}
+	return 0
+}
+
+func (x *ClientSettings) GetKeepaliveSeconds() uint32 {
+	if x != nil {
+		return x.KeepaliveSeconds
+	}
+	return 0
+}
+
+type CloseResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pid     uint32              `protobuf:"varint,1,opt,name=pid,proto3" json:"pid,omitempty"`
+	Name    string              `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Outcome CloseResult_Outcome `protobuf:"varint,3,opt,name=outcome,proto3,enum=monitor.CloseResult_Outcome" json:"outcome,omitempty"`
+	Error   string              `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *CloseResult) Reset() {
+	*x = CloseResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_monitor_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CloseResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloseResult) ProtoMessage() {}
+
+func (x *CloseResult) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloseResult.ProtoReflect.Descriptor instead.
+func (*CloseResult) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CloseResult) GetPid() uint32 {
+	if x != nil {
+		return x.Pid
+	}
+	return 0
+}
+
+func (x *CloseResult) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CloseResult) GetOutcome() CloseResult_Outcome {
+	if x != nil {
+		return x.Outcome
+	}
+	return CloseResult_CLOSED
+}
+
+func (x *CloseResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// ProcessRules decide which foreground processes count as a game.
+// Rules are checked in order and the first match wins; unmatched processes are tracked.
+type ProcessRules struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version int64          `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	Rules   []*ProcessRule `protobuf:"bytes,2,rep,name=rules,proto3" json:"rules,omitempty"`
+}
+
+func (x *ProcessRules) Reset() {
+	*x = ProcessRules{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_monitor_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProcessRules) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProcessRules) ProtoMessage() {}
+
+func (x *ProcessRules) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProcessRules.ProtoReflect.Descriptor instead.
+func (*ProcessRules) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ProcessRules) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *ProcessRules) GetRules() []*ProcessRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+type ProcessRule struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pattern string             `protobuf:"bytes,1,opt,name=pattern,proto3" json:"pattern,omitempty"`
+	Match   ProcessRule_Match  `protobuf:"varint,2,opt,name=match,proto3,enum=monitor.ProcessRule_Match" json:"match,omitempty"`
+	Action  ProcessRule_Action `protobuf:"varint,3,opt,name=action,proto3,enum=monitor.ProcessRule_Action" json:"action,omitempty"`
+}
+
+func (x *ProcessRule) Reset() {
+	*x = ProcessRule{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_monitor_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProcessRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProcessRule) ProtoMessage() {}
+
+func (x *ProcessRule) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProcessRule.ProtoReflect.Descriptor instead.
+func (*ProcessRule) Descriptor() ([]byte, []int) {
+	return file_monitor_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ProcessRule) GetPattern() string {
+	if x != nil {
+		return x.Pattern
+	}
+	return ""
+}
+
+func (x *ProcessRule) GetMatch() ProcessRule_Match {
+	if x != nil {
+		return x.Match
+	}
+	return ProcessRule_NAME_GLOB
+}
+
+func (x *ProcessRule) GetAction() ProcessRule_Action {
+	if x != nil {
+		return x.Action
+	}
+	return ProcessRule_IGNORE
+}
+
 var File_monitor_proto protoreflect.FileDescriptor
 
 var file_monitor_proto_rawDesc = []byte{
 	0x0a, 0x0d, 0x6d, 0x6f, 0x6e, 0x69, 0x74, 0x6f, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
-	0x07, 0x6d, 0x6f, 0x6e, 0x69, 0x74, 0x6f, 0x72, 0x22, 0x61, 0x0a, 0x09, 0x48, 0x65, 0x61, 0x72,
-	0x74, 0x62, 0x65, 0x61, 0x74, 0x12, 0x13, 0x0a, 0x05, 0x70, 0x63, 0x5f, 0x69, 0x64, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x63, 0x49, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x75,
-	0x72, 0x72, 0x65, 0x6e, 0x74, 0x5f, 0x67, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x0b, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x47, 0x61, 0x6d, 0x65, 0x12, 0x1c, 0x0a,
-	0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03,
-	0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x22, 0x3d, 0x0a, 0x0f, 0x43,
-	0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2a,
-	0x0a, 0x11, 0x63, 0x6c, 0x6f, 0x73, 0x65, 0x5f, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x5f, 0x67,
-	0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x63, 0x6c, 0x6f, 0x73, 0x65,
-	0x41, 0x63, 0x74, 0x69, 0x76, 0x65, 0x47, 0x61, 0x6d, 0x65, 0x32, 0x51, 0x0a, 0x0c, 0x4e, 0x65,
-	0x78, 0x75, 0x73, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x41, 0x0a, 0x0d, 0x53, 0x74,
-	0x72, 0x65, 0x61, 0x6d, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x2e, 0x6d, 0x6f,
-	0x6e, 0x69, 0x74, 0x6f, 0x72, 0x2e, 0x48, 0x65, 0x61, 0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x1a,
-	0x18, 0x2e, 0x6d, 0x6f, 0x6e, 0x69, 0x74, 0x6f, 0x72, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e,
-	0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x28, 0x01, 0x30, 0x01, 0x42, 0x32, 0x5a,
-	0x30, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x4d, 0x6f, 0x68, 0x61,
-	0x6d, 0x6d, 0x61, 0x64, 0x2d, 0x4d, 0x61, 0x68, 0x64, 0x69, 0x38, 0x32, 0x2f, 0x4e, 0x65, 0x78,
-	0x75, 0x73, 0x4f, 0x70, 0x73, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x6d, 0x6f, 0x6e, 0x69, 0x74, 0x6f,
-	0x72, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x07, 0x6d, 0x6f, 0x6e, 0x69, 0x74, 0x6f, 0x72, 0x22, 0xbb, 0x02, 0x0a, 0x09, 0x48, 0x65, 0x61,
+	0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x12, 0x13, 0x0a, 0x05, 0x70, 0x63, 0x5f, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x63, 0x49, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x63,
+	0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x5f, 0x67, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0b, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x47, 0x61, 0x6d, 0x65, 0x12, 0x1c,
+	0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x23, 0x0a, 0x0d,
+	0x72, 0x75, 0x6c, 0x65, 0x73, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x0c, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x12, 0x2a, 0x0a, 0x05, 0x67, 0x61, 0x6d, 0x65, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x14, 0x2e, 0x6d, 0x6f, 0x6e, 0x69, 0x74, 0x6f, 0x72, 0x2e, 0x47, 0x61, 0x6d, 0x65, 0x50,
+	0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x52, 0x05, 0x67, 0x61, 0x6d, 0x65, 0x73, 0x12, 0x39, 0x0a,
+	0x0d, 0x63, 0x6c, 0x6f, 0x73, 0x65, 0x5f, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x06,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6d, 0x6f, 0x6e, 0x69, 0x74, 0x6f, 0x72, 0x2e, 0x43,
+	0x6c, 0x6f, 0x73, 0x65, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x0c, 0x63, 0x6c, 0x6f, 0x73,
+	0x65, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x69, 0x64, 0x6c, 0x65,
+	0x5f, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b,
+	0x69, 0x64, 0x6c, 0x65, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x12, 0x29, 0x0a, 0x10, 0x73,
+	0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x73, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x56,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x9f, 0x01, 0x0a, 0x0b, 0x47, 0x61, 0x6d, 0x65, 0x50,
+	0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x70, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x03, 0x70, 0x69, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x61, 0x72, 0x65,
+	0x6e, 0x74, 0x5f, 0x70, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x70, 0x61,
+	0x72, 0x65, 0x6e, 0x74, 0x50, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x70,
+	0x61, 0x74, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12,
+	0x1d, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x09, 0x73, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x18,
+	0x0a, 0x07, 0x66, 0x6f, 0x63, 0x75, 0x73, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x07, 0x66, 0x6f, 0x63, 0x75, 0x73, 0x65, 0x64, 0x22, 0xec, 0x02, 0x0a, 0x0f, 0x43, 0x6f, 0x6d,
+	0x6d, 0x61, 0x6e, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2a, 0x0a, 0x11,
+	0x63, 0x6c, 0x6f, 0x73, 0x65, 0x5f, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x5f, 0x67, 0x61, 0x6d,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x63, 0x6c, 0x6f, 0x73, 0x65, 0x41, 0x63,
+	0x74, 0x69, 0x76, 0x65, 0x47, 0x61, 0x6d, 0x65, 0x12, 0x3a, 0x0a, 0x0d, 0x70, 0x72, 0x6f, 0x63,
+	0x65, 0x73, 0x73, 0x5f, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x15, 0x2e, 0x6d, 0x6f, 0x6e, 0x69, 0x74, 0x6f, 0x72, 0x2e, 0x50, 0x72, 0x6f, 0x63, 0x65, 0x73,
+	0x73, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x52, 0x0c, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x52,
+	0x75, 0x6c, 0x65, 0x73, 0x12, 0x35, 0x0a, 0x0b, 0x63, 0x6c, 0x6f, 0x73, 0x65, 0x5f, 0x67, 0x61,
+	0x6d, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6d, 0x6f, 0x6e, 0x69,
+	0x74, 0x6f, 0x72, 0x2e, 0x47, 0x61, 0x6d, 0x65, 0x50, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x52,
+	0x0a, 0x63, 0x6c, 0x6f, 0x73, 0x65, 0x47, 0x61, 0x6d, 0x65, 0x73, 0x12, 0x32, 0x0a, 0x15, 0x63,
+	0x6c, 0x6f, 0x73, 0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x5f, 0x73, 0x65, 0x63,
+	0x6f, 0x6e, 0x64, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x13, 0x63, 0x6c, 0x6f, 0x73,
+	0x65, 0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x12,
+	0x16, 0x0a, 0x06, 0x6e, 0x6f, 0x74, 0x69, 0x63, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x6e, 0x6f, 0x74, 0x69, 0x63, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x6f, 0x63, 0x6b, 0x65,
+	0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x6c, 0x6f, 0x63, 0x6b, 0x65, 0x64, 0x12,
+	0x21, 0x0a, 0x0c, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x6c, 0x6f, 0x63, 0x6b, 0x4d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x12, 0x33, 0x0a, 0x08, 0x73, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x18, 0x08,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6d, 0x6f, 0x6e, 0x69, 0x74, 0x6f, 0x72, 0x2e, 0x43,
+	0x6c, 0x69, 0x65, 0x6e, 0x74, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x52, 0x08, 0x73,
+	0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x22, 0xd1, 0x01, 0x0a, 0x0e, 0x43, 0x6c, 0x69, 0x65,
+	0x6e, 0x74, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x76, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x12, 0x3c, 0x0a, 0x1a, 0x68, 0x65, 0x61, 0x72, 0x74, 0x62, 0x65, 0x61,
+	0x74, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x5f, 0x73, 0x65, 0x63, 0x6f, 0x6e,
+	0x64, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x18, 0x68, 0x65, 0x61, 0x72, 0x74, 0x62,
+	0x65, 0x61, 0x74, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x53, 0x65, 0x63, 0x6f, 0x6e,
+	0x64, 0x73, 0x12, 0x3a, 0x0a, 0x19, 0x64, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x5f,
+	0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x5f, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x17, 0x64, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79,
+	0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x12, 0x2b,
+	0x0a, 0x11, 0x6b, 0x65, 0x65, 0x70, 0x61, 0x6c, 0x69, 0x76, 0x65, 0x5f, 0x73, 0x65, 0x63, 0x6f,
+	0x6e, 0x64, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x10, 0x6b, 0x65, 0x65, 0x70, 0x61,
+	0x6c, 0x69, 0x76, 0x65, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x22, 0xc1, 0x01, 0x0a, 0x0b,
+	0x43, 0x6c, 0x6f, 0x73, 0x65, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x70,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x03, 0x70, 0x69, 0x64, 0x12, 0x12, 0x0a,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x12, 0x36, 0x0a, 0x07, 0x6f, 0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x1c, 0x2e, 0x6d, 0x6f, 0x6e, 0x69, 0x74, 0x6f, 0x72, 0x2e, 0x43, 0x6c, 0x6f,
+	0x73, 0x65, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x2e, 0x4f, 0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65,
+	0x52, 0x07, 0x6f, 0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72,
+	0x6f, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22,
+	0x3e, 0x0a, 0x07, 0x4f, 0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65, 0x12, 0x0a, 0x0a, 0x06, 0x43, 0x4c,
+	0x4f, 0x53, 0x45, 0x44, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x4b, 0x49, 0x4c, 0x4c, 0x45, 0x44,
+	0x10, 0x01, 0x12, 0x0a, 0x0a, 0x06, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44, 0x10, 0x02, 0x12, 0x0f,
+	0x0a, 0x0b, 0x4e, 0x4f, 0x54, 0x5f, 0x52, 0x55, 0x4e, 0x4e, 0x49, 0x4e, 0x47, 0x10, 0x03, 0x22,
+	0x54, 0x0a, 0x0c, 0x50, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x12,
+	0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x2a, 0x0a, 0x05, 0x72, 0x75, 0x6c,
+	0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6d, 0x6f, 0x6e, 0x69, 0x74,
+	0x6f, 0x72, 0x2e, 0x50, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x05,
+	0x72, 0x75, 0x6c, 0x65, 0x73, 0x22, 0xe1, 0x01, 0x0a, 0x0b, 0x50, 0x72, 0x6f, 0x63, 0x65, 0x73,
+	0x73, 0x52, 0x75, 0x6c, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x12,
+	0x30, 0x0a, 0x05, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1a,
+	0x2e, 0x6d, 0x6f, 0x6e, 0x69, 0x74, 0x6f, 0x72, 0x2e, 0x50, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73,
+	0x52, 0x75, 0x6c, 0x65, 0x2e, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x52, 0x05, 0x6d, 0x61, 0x74, 0x63,
+	0x68, 0x12, 0x33, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x1b, 0x2e, 0x6d, 0x6f, 0x6e, 0x69, 0x74, 0x6f, 0x72, 0x2e, 0x50, 0x72, 0x6f, 0x63,
+	0x65, 0x73, 0x73, 0x52, 0x75, 0x6c, 0x65, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x06,
+	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x30, 0x0a, 0x05, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x12,
+	0x0d, 0x0a, 0x09, 0x4e, 0x41, 0x4d, 0x45, 0x5f, 0x47, 0x4c, 0x4f, 0x42, 0x10, 0x00, 0x12, 0x0d,
+	0x0a, 0x09, 0x50, 0x41, 0x54, 0x48, 0x5f, 0x47, 0x4c, 0x4f, 0x42, 0x10, 0x01, 0x12, 0x09, 0x0a,
+	0x05, 0x52, 0x45, 0x47, 0x45, 0x58, 0x10, 0x02, 0x22, 0x1f, 0x0a, 0x06, 0x41, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x0a, 0x0a, 0x06, 0x49, 0x47, 0x4e, 0x4f, 0x52, 0x45, 0x10, 0x00, 0x12, 0x09,
+	0x0a, 0x05, 0x54, 0x52, 0x41, 0x43, 0x4b, 0x10, 0x01, 0x32, 0x51, 0x0a, 0x0c, 0x4e, 0x65, 0x78,
+	0x75, 0x73, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x41, 0x0a, 0x0d, 0x53, 0x74, 0x72,
+	0x65, 0x61, 0x6d, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x2e, 0x6d, 0x6f, 0x6e,
+	0x69, 0x74, 0x6f, 0x72, 0x2e, 0x48, 0x65, 0x61, 0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x1a, 0x18,
+	0x2e, 0x6d, 0x6f, 0x6e, 0x69, 0x74, 0x6f, 0x72, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x28, 0x01, 0x30, 0x01, 0x42, 0x32, 0x5a, 0x30,
+	0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x4d, 0x6f, 0x68, 0x61, 0x6d,
+	0x6d, 0x61, 0x64, 0x2d, 0x4d, 0x61, 0x68, 0x64, 0x69, 0x38, 0x32, 0x2f, 0x4e, 0x65, 0x78, 0x75,
+	0x73, 0x4f, 0x70, 0x73, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x6d, 0x6f, 0x6e, 0x69, 0x74, 0x6f, 0x72,
+	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -168,19 +854,37 @@ func file_monitor_proto_rawDescGZIP() []byte {
 	return file_monitor_proto_rawDescData
 }
 
-var file_monitor_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_monitor_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_monitor_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
 var file_monitor_proto_goTypes = []interface{}{
-	(*Heartbeat)(nil),       // 0: monitor.Heartbeat
-	(*CommandResponse)(nil), // 1: monitor.CommandResponse
+	(CloseResult_Outcome)(0), // 0: monitor.CloseResult.Outcome
+	(ProcessRule_Match)(0),   // 1: monitor.ProcessRule.Match
+	(ProcessRule_Action)(0),  // 2: monitor.ProcessRule.Action
+	(*Heartbeat)(nil),        // 3: monitor.Heartbeat
+	(*GameProcess)(nil),      // 4: monitor.GameProcess
+	(*CommandResponse)(nil),  // 5: monitor.CommandResponse
+	(*ClientSettings)(nil),   // 6: monitor.ClientSettings
+	(*CloseResult)(nil),      // 7: monitor.CloseResult
+	(*ProcessRules)(nil),     // 8: monitor.ProcessRules
+	(*ProcessRule)(nil),      // 9: monitor.ProcessRule
 }
 var file_monitor_proto_depIdxs = []int32{
-	0, // 0: monitor.NexusService.StreamSession:input_type -> monitor.Heartbeat
-	1, // 1: monitor.NexusService.StreamSession:output_type -> monitor.CommandResponse
-	1, // [1:2] is the sub-list for method output_type
-	0, // [0:1] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	4,  // 0: monitor.Heartbeat.games:type_name -> monitor.GameProcess
+	7,  // 1: monitor.Heartbeat.close_results:type_name -> monitor.CloseResult
+	8,  // 2: monitor.CommandResponse.process_rules:type_name -> monitor.ProcessRules
+	4,  // 3: monitor.CommandResponse.close_games:type_name -> monitor.GameProcess
+	6,  // 4: monitor.CommandResponse.settings:type_name -> monitor.ClientSettings
+	0,  // 5: monitor.CloseResult.outcome:type_name -> monitor.CloseResult.Outcome
+	9,  // 6: monitor.ProcessRules.rules:type_name -> monitor.ProcessRule
+	1,  // 7: monitor.ProcessRule.match:type_name -> monitor.ProcessRule.Match
+	2,  // 8: monitor.ProcessRule.action:type_name -> monitor.ProcessRule.Action
+	3,  // 9: monitor.NexusService.StreamSession:input_type -> monitor.Heartbeat
+	5,  // 10: monitor.NexusService.StreamSession:output_type -> monitor.CommandResponse
+	10, // [10:11] is the sub-list for method output_type
+	9,  // [9:10] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
 }
 
 func init() { file_monitor_proto_init() }
@@ -202,6 +906,18 @@ func file_monitor_proto_init() {
 			}
 		}
 		file_monitor_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GameProcess); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_monitor_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*CommandResponse); i {
 			case 0:
 				return &v.state
@@ -213,19 +929,68 @@ func file_monitor_proto_init() {
 				return nil
 			}
 		}
+		file_monitor_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ClientSettings); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_monitor_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CloseResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_monitor_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProcessRules); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_monitor_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProcessRule); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_monitor_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   2,
+			NumEnums:      3,
+			NumMessages:   7,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_monitor_proto_goTypes,
 		DependencyIndexes: file_monitor_proto_depIdxs,
+		EnumInfos:         file_monitor_proto_enumTypes,
 		MessageInfos:      file_monitor_proto_msgTypes,
 	}.Build()
 	File_monitor_proto = out.File
@@ -0,0 +1,12 @@
+// Package processrules holds the process rules the server and the Sentry share.
+package processrules
+
+// DefaultIgnored names the shells, system tasks and launchers that are never a game.
+// The server seeds its process_rules with them, and a Sentry uses them until the server
+// has pushed its rules.
+var DefaultIgnored = []string{
+	"explorer.exe", "searchhost.exe", "shellexperiencehost.exe", "lockapp.exe", "taskhostw.exe",
+	"cmd.exe", "powershell.exe", "pwsh.exe", "windowsterminal.exe", "conhost.exe", "taskmgr.exe",
+	"steam.exe", "steamwebhelper.exe", "epicgameslauncher.exe", "origin.exe", "battle.net.exe",
+	"discord.exe",
+}
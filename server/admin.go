@@ -0,0 +1,604 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	pb "github.com/Mohammad-Mahdi82/NexusOps/pkg/monitor"
+	"github.com/Mohammad-Mahdi82/NexusOps/server/models"
+	"github.com/shopspring/decimal"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+// dashboardCoalesce is how long a watcher waits after a change for more to pile up,
+// so a burst of heartbeats becomes one dashboard instead of dozens.
+const dashboardCoalesce = 200 * time.Millisecond
+
+// adminServer is the AdminService the console and scripts use. It is served on its own
+// listener so the port Sentries connect to doesn't expose operator actions.
+type adminServer struct {
+	pb.UnimplementedAdminServiceServer
+	s *server
+}
+
+// unpaidSessions reads a PC's unpaid sessions, with the running one taken from memory
+// because the DB copy can be a flush behind. Callers must hold s.mu.
+func (s *server) unpaidSessions(pcID string) []models.Session {
+	var sessions []models.Session
+	s.db.Where("pc_id = ? AND paid = ?", pcID, false).Order("start_time asc").Find(&sessions)
+	if ls := s.liveSessions[pcID]; ls != nil {
+		for i := range sessions {
+			if sessions[i].ID == ls.sess.ID {
+				sessions[i] = ls.sess
+			}
+		}
+	}
+	return sessions
+}
+
+// dashboard snapshots every connected PC. Callers must hold s.mu.
+func (s *server) dashboard() *pb.Dashboard {
+	d := &pb.Dashboard{PendingGames: s.pendingCount()}
+
+	for _, pcID := range s.knownStations(true) {
+		d.Stations = append(d.Stations, s.stationView(pcID))
+	}
+	d.TodayRevenue = s.revenueSince(startOfDay(time.Now())).String()
+	return d
+}
+
+// revenueSince totals the payments taken from t on.
+func (s *server) revenueSince(t time.Time) decimal.Decimal {
+	var payments []models.Payment
+	s.db.Select("total").Where("created_at >= ?", t).Find(&payments)
+	total := decimal.Zero
+	for _, p := range payments {
+		total = total.Add(p.Total)
+	}
+	return total
+}
+
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// pendingCount is the number of executables waiting to be classified. Callers must hold s.mu.
+func (s *server) pendingCount() int32 {
+	var n int32
+	for _, g := range s.catalog {
+		if !g.Classified {
+			n++
+		}
+	}
+	return n
+}
+
+// knownStations lists every PC with a station record or a connection, sorted by ID.
+// Callers must hold s.mu.
+func (s *server) knownStations(connectedOnly bool) []string {
+	seen := make(map[string]bool)
+	for id := range s.pcStates {
+		seen[id] = true
+	}
+	if !connectedOnly {
+		for id := range s.stations {
+			seen[id] = true
+		}
+	}
+	ids := make([]string, 0, len(seen))
+	for id := range seen {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// stationView describes one PC for the console. Callers must hold s.mu.
+func (s *server) stationView(pcID string) *pb.Station {
+	game, connected := s.pcStates[pcID]
+	view := &pb.Station{
+		Id:        pcID,
+		Connected: connected,
+		Game:      game,
+		Notice:    s.pcNotices[pcID],
+		Locked:    s.stationLocked(pcID, time.Now()),
+	}
+	if st, ok := s.stations[pcID]; ok {
+		view.Open = st.Open
+		if st.PrepaidUntil != nil {
+			view.PrepaidUntil = st.PrepaidUntil.Unix()
+		}
+		view.IdleOverrides = &pb.IdlePolicy{
+			WarnMinutes:  int32Ptr(st.IdleWarnMinutes),
+			PauseMinutes: int32Ptr(st.IdlePauseMinutes),
+			LockMinutes:  int32Ptr(st.IdleLockMinutes),
+		}
+		if !st.LastSeen.IsZero() {
+			view.LastSeen = st.LastSeen.Unix()
+		}
+	}
+
+	total := decimal.Zero
+	for _, sess := range s.unpaidSessions(pcID) {
+		view.Unpaid = append(view.Unpaid, toPBSession(sess))
+		total = total.Add(sess.Fee)
+	}
+	view.UnpaidTotal = total.String()
+	return view
+}
+
+func toPBSession(sess models.Session) *pb.Session {
+	return &pb.Session{
+		Id:              sess.ID,
+		PcId:            sess.PcID,
+		GameName:        sess.GameName,
+		GameTitle:       sess.GameTitle,
+		RateClass:       sess.RateClass,
+		StartTime:       sess.StartTime.Unix(),
+		EndTime:         sess.EndTime.Unix(),
+		DurationMinutes: int32(sess.DurationMinutes),
+		PausedSeconds:   int32(sess.PausedSeconds),
+		Fee:             sess.Fee.String(),
+		Active:          sess.IsActive,
+		Paid:            sess.Paid,
+		PaymentId:       sess.PaymentID,
+	}
+}
+
+func toPBPayment(p models.Payment) *pb.Payment {
+	return &pb.Payment{
+		Id:        p.ID,
+		PcId:      p.PcID,
+		Subtotal:  p.Subtotal.String(),
+		Discount:  p.Discount.String(),
+		Tax:       p.Tax.String(),
+		Total:     p.Total.String(),
+		Method:    p.Method,
+		Operator:  p.Operator,
+		CreatedAt: p.CreatedAt.Unix(),
+	}
+}
+
+func toPBGame(g models.Game) *pb.Game {
+	return &pb.Game{
+		Executable: g.Executable,
+		Title:      g.Title,
+		Genre:      g.Genre,
+		RateClass:  g.RateClass,
+		Classified: g.Classified,
+		NotGame:    g.NotGame,
+	}
+}
+
+func int32Ptr(v *int) *int32 {
+	if v == nil {
+		return nil
+	}
+	n := int32(*v)
+	return &n
+}
+
+func intPtr(v *int32) *int {
+	if v == nil {
+		return nil
+	}
+	n := int(*v)
+	return &n
+}
+
+// stationReply is what station-changing calls return.
+func (s *server) stationReply(pcID string) *pb.Station {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stationView(pcID)
+}
+
+func (a *adminServer) GetConsoleConfig(context.Context, *pb.ConsoleConfigRequest) (*pb.ConsoleConfig, error) {
+	cfg := a.s.cfg
+	return &pb.ConsoleConfig{
+		Language:       cfg.Locale.Language,
+		Calendar:       cfg.Locale.Calendar,
+		PersianDigits:  cfg.Locale.PersianDigits,
+		PaymentMethods: cfg.Receipt.PaymentMethods,
+		RateClasses:    a.s.rateClasses(),
+		IdleDefaults: &pb.IdlePolicy{
+			WarnMinutes:  int32Ptr(&cfg.Idle.WarnMinutes),
+			PauseMinutes: int32Ptr(&cfg.Idle.PauseMinutes),
+			LockMinutes:  int32Ptr(&cfg.Idle.LockMinutes),
+		},
+		LockEnabled: cfg.Lock.Enabled,
+	}, nil
+}
+
+// WatchDashboard rebuilds the whole dashboard after every burst of events. Clients that
+// can apply events themselves should use WatchEvents instead.
+func (a *adminServer) WatchDashboard(_ *pb.WatchDashboardRequest, stream pb.AdminService_WatchDashboardServer) error {
+	ctx := stream.Context()
+	for {
+		a.s.mu.Lock()
+		events := a.s.subscribe()
+		d := a.s.dashboard()
+		a.s.mu.Unlock()
+		if err := stream.Send(d); err != nil {
+			a.s.unsubscribe(events)
+			return err
+		}
+
+		select {
+		case <-events:
+		case <-ctx.Done():
+			a.s.unsubscribe(events)
+			return nil
+		}
+		select {
+		case <-time.After(dashboardCoalesce):
+		case <-ctx.Done():
+			a.s.unsubscribe(events)
+			return nil
+		}
+		// Whatever piled up is covered by the next dashboard
+		a.s.unsubscribe(events)
+	}
+}
+
+func (a *adminServer) WatchEvents(_ *pb.WatchEventsRequest, stream pb.AdminService_WatchEventsServer) error {
+	a.s.mu.Lock()
+	events := a.s.subscribe()
+	snapshot := &pb.Event{Type: pb.Event_SNAPSHOT, Time: time.Now().Unix(), Dashboard: a.s.dashboard()}
+	a.s.mu.Unlock()
+	defer a.s.unsubscribe(events)
+
+	if err := stream.Send(snapshot); err != nil {
+		return err
+	}
+	ctx := stream.Context()
+	for {
+		select {
+		case ev, ok := <-events:
+			if !ok {
+				return status.Error(codes.ResourceExhausted, "fell too far behind, watch again")
+			}
+			if err := stream.Send(ev); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (a *adminServer) RecordPayment(ctx context.Context, req *pb.RecordPaymentRequest) (*pb.RecordPaymentResponse, error) {
+	if req.PcId == "" {
+		return nil, status.Error(codes.InvalidArgument, "pc_id is required")
+	}
+	method := req.Method
+	if method == "" && len(a.s.cfg.Receipt.PaymentMethods) > 0 {
+		method = a.s.cfg.Receipt.PaymentMethods[0]
+	}
+	discount := decimal.Zero
+	if req.Discount != "" {
+		d, err := decimal.NewFromString(req.Discount)
+		if err != nil || d.IsNegative() {
+			return nil, status.Errorf(codes.InvalidArgument, "discount must be a positive amount, not %q", req.Discount)
+		}
+		discount = d
+	}
+
+	payment, err := a.s.MarkAsPaid(req.PcId, method, operatorFrom(ctx), discount)
+	if errors.Is(err, errDiscountTooLarge) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "payment failed: %v", err)
+	}
+	resp := &pb.RecordPaymentResponse{}
+	if payment != nil {
+		resp.Payment = toPBPayment(*payment)
+	}
+	return resp, nil
+}
+
+func (a *adminServer) SetStationOpen(_ context.Context, req *pb.SetStationOpenRequest) (*pb.Station, error) {
+	if req.PcId == "" {
+		return nil, status.Error(codes.InvalidArgument, "pc_id is required")
+	}
+	if err := a.s.setStationOpen(req.PcId, req.Open); err != nil {
+		return nil, status.Errorf(codes.Internal, "station not updated: %v", err)
+	}
+	a.s.sendCommand(req.PcId)
+	a.s.announce(pb.Event_COMMAND_ISSUED, req.PcId, func(ev *pb.Event) {
+		ev.Command = pb.SendCommandRequest_LOCK.String()
+		if req.Open {
+			ev.Command = pb.SendCommandRequest_UNLOCK.String()
+		}
+	})
+	return a.s.stationReply(req.PcId), nil
+}
+
+func (a *adminServer) StartTimedSession(_ context.Context, req *pb.StartTimedSessionRequest) (*pb.Station, error) {
+	if req.PcId == "" || req.Minutes <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "pc_id and a positive minutes are required")
+	}
+	if err := a.s.addPrepaidTime(req.PcId, time.Duration(req.Minutes)*time.Minute); err != nil {
+		return nil, status.Errorf(codes.Internal, "timed access not saved: %v", err)
+	}
+	a.s.sendCommand(req.PcId)
+	a.s.announce(pb.Event_COMMAND_ISSUED, req.PcId, func(ev *pb.Event) { ev.Command = "TIMED" })
+	return a.s.stationReply(req.PcId), nil
+}
+
+func (a *adminServer) SetIdlePolicy(_ context.Context, req *pb.SetIdlePolicyRequest) (*pb.Station, error) {
+	if req.PcId == "" {
+		return nil, status.Error(codes.InvalidArgument, "pc_id is required")
+	}
+	p := req.Policy
+	if p == nil {
+		p = &pb.IdlePolicy{}
+	}
+	for _, v := range []*int32{p.WarnMinutes, p.PauseMinutes, p.LockMinutes} {
+		if v != nil && *v < 0 {
+			return nil, status.Error(codes.InvalidArgument, "idle minutes can't be negative")
+		}
+	}
+	if err := a.s.setIdlePolicy(req.PcId, intPtr(p.WarnMinutes), intPtr(p.PauseMinutes), intPtr(p.LockMinutes)); err != nil {
+		return nil, status.Errorf(codes.Internal, "idle policy not saved: %v", err)
+	}
+	a.s.announce(pb.Event_STATION_UPDATED, req.PcId, nil)
+	return a.s.stationReply(req.PcId), nil
+}
+
+func (a *adminServer) ListPendingGames(context.Context, *pb.ListPendingGamesRequest) (*pb.ListPendingGamesResponse, error) {
+	resp := &pb.ListPendingGamesResponse{}
+	for _, g := range a.s.pendingGames() {
+		resp.Games = append(resp.Games, toPBGame(g))
+	}
+	return resp, nil
+}
+
+func (a *adminServer) ClassifyGame(_ context.Context, req *pb.ClassifyGameRequest) (*pb.Game, error) {
+	if req.Executable == "" {
+		return nil, status.Error(codes.InvalidArgument, "executable is required")
+	}
+	rateClass := req.RateClass
+	if rateClass == "" {
+		rateClass = DefaultRateClass
+	}
+	if _, ok := a.s.cfg.Rates[rateClass]; !ok && rateClass != DefaultRateClass {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown rate class %q", rateClass)
+	}
+	title := req.Title
+	if title == "" {
+		title = req.Executable
+	}
+
+	if err := a.s.classifyGame(req.Executable, title, req.Genre, rateClass, req.NotGame); err != nil {
+		return nil, status.Errorf(codes.Internal, "classification failed: %v", err)
+	}
+	return &pb.Game{Executable: strings.ToLower(req.Executable), Title: title, Genre: req.Genre, RateClass: rateClass, Classified: true, NotGame: req.NotGame}, nil
+}
+
+func (a *adminServer) ListStations(_ context.Context, req *pb.ListStationsRequest) (*pb.ListStationsResponse, error) {
+	a.s.mu.Lock()
+	defer a.s.mu.Unlock()
+
+	resp := &pb.ListStationsResponse{}
+	for _, pcID := range a.s.knownStations(req.ConnectedOnly) {
+		resp.Stations = append(resp.Stations, a.s.stationView(pcID))
+	}
+	return resp, nil
+}
+
+func (a *adminServer) ListSessions(_ context.Context, req *pb.ListSessionsRequest) (*pb.ListSessionsResponse, error) {
+	limit, err := pageSize(req.Limit)
+	if err != nil {
+		return nil, err
+	}
+	// One extra row tells whether there is another page
+	q, err := afterPage(a.s.db.Order("start_time desc, id desc").Limit(limit+1), "start_time", req.PageToken)
+	if err != nil {
+		return nil, err
+	}
+	if req.PcId != "" {
+		q = q.Where("pc_id = ?", req.PcId)
+	}
+	if req.Paid != nil {
+		q = q.Where("paid = ?", *req.Paid)
+	}
+	if req.From > 0 {
+		q = q.Where("start_time >= ?", time.Unix(req.From, 0))
+	}
+	if req.To > 0 {
+		q = q.Where("start_time < ?", time.Unix(req.To, 0))
+	}
+
+	a.s.mu.Lock()
+	defer a.s.mu.Unlock()
+	var sessions []models.Session
+	if err := q.Find(&sessions).Error; err != nil {
+		return nil, status.Errorf(codes.Internal, "sessions not read: %v", err)
+	}
+	resp := &pb.ListSessionsResponse{}
+	if len(sessions) > limit {
+		sessions = sessions[:limit]
+		last := sessions[limit-1]
+		resp.NextPageToken = pageToken(last.StartTime, last.ID)
+	}
+	for _, sess := range sessions {
+		// The DB copy of a running session can be a flush behind
+		if ls := a.s.liveSessions[sess.PcID]; ls != nil && ls.sess.ID == sess.ID {
+			sess = ls.sess
+		}
+		resp.Sessions = append(resp.Sessions, toPBSession(sess))
+	}
+	return resp, nil
+}
+
+func (a *adminServer) UpdateSession(_ context.Context, req *pb.UpdateSessionRequest) (*pb.Session, error) {
+	if req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+	var edit SessionEdit
+	if req.RateClass != nil {
+		if _, ok := a.s.cfg.Rates[*req.RateClass]; !ok && *req.RateClass != DefaultRateClass {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown rate class %q", *req.RateClass)
+		}
+		edit.RateClass = req.RateClass
+	}
+	if req.DurationMinutes != nil {
+		if *req.DurationMinutes < 0 {
+			return nil, status.Error(codes.InvalidArgument, "duration can't be negative")
+		}
+		edit.DurationMinutes = intPtr(req.DurationMinutes)
+	}
+	if req.Fee != nil {
+		fee, err := decimal.NewFromString(*req.Fee)
+		if err != nil || fee.IsNegative() {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid fee %q", *req.Fee)
+		}
+		edit.Fee = &fee
+	}
+
+	sess, err := a.s.editSession(req.Id, edit)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, status.Errorf(codes.NotFound, "no session %q", req.Id)
+	case errors.Is(err, errSessionRunning), errors.Is(err, errSessionPaid):
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	case err != nil:
+		return nil, status.Errorf(codes.Internal, "session not saved: %v", err)
+	}
+	a.s.announce(pb.Event_SESSION_UPDATED, sess.PcID, func(ev *pb.Event) { ev.Session = toPBSession(*sess) })
+	return toPBSession(*sess), nil
+}
+
+func (a *adminServer) SendCommand(_ context.Context, req *pb.SendCommandRequest) (*pb.SendCommandResponse, error) {
+	if req.PcId == "" {
+		return nil, status.Error(codes.InvalidArgument, "pc_id is required")
+	}
+	a.s.mu.Lock()
+	known := a.s.stationKnown(req.PcId)
+	a.s.mu.Unlock()
+	if !known {
+		return nil, status.Errorf(codes.NotFound, "no station %q", req.PcId)
+	}
+
+	var delivered bool
+	switch req.Action {
+	case pb.SendCommandRequest_MESSAGE:
+		if strings.TrimSpace(req.Text) == "" {
+			return nil, status.Error(codes.InvalidArgument, "text is required")
+		}
+		delivered = a.s.sendNotice(req.PcId, req.Text)
+	case pb.SendCommandRequest_CLOSE_GAMES:
+		delivered = a.s.closeGames(req.PcId)
+	case pb.SendCommandRequest_LOCK, pb.SendCommandRequest_UNLOCK:
+		if err := a.s.setStationOpen(req.PcId, req.Action == pb.SendCommandRequest_UNLOCK); err != nil {
+			return nil, status.Errorf(codes.Internal, "station not updated: %v", err)
+		}
+		delivered = a.s.sendCommand(req.PcId)
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown action %v", req.Action)
+	}
+	a.s.announce(pb.Event_COMMAND_ISSUED, req.PcId, func(ev *pb.Event) { ev.Command = req.Action.String() })
+	return &pb.SendCommandResponse{Delivered: delivered, Station: a.s.stationReply(req.PcId)}, nil
+}
+
+func (a *adminServer) GetStation(_ context.Context, req *pb.GetStationRequest) (*pb.Station, error) {
+	a.s.mu.Lock()
+	defer a.s.mu.Unlock()
+	if !a.s.stationKnown(req.Id) {
+		return nil, status.Errorf(codes.NotFound, "no station %q", req.Id)
+	}
+	return a.s.stationView(req.Id), nil
+}
+
+// stationKnown tells whether a PC has a station row or is connected right now. Calls
+// that would save a station check it first, so a mistyped ID doesn't become a phantom
+// PC. Callers must hold s.mu.
+func (s *server) stationKnown(pcID string) bool {
+	_, known := s.stations[pcID]
+	_, connected := s.pcStates[pcID]
+	return known || connected
+}
+
+func (a *adminServer) GetSession(_ context.Context, req *pb.GetSessionRequest) (*pb.Session, error) {
+	a.s.mu.Lock()
+	defer a.s.mu.Unlock()
+	for _, ls := range a.s.liveSessions {
+		if ls.sess.ID == req.Id {
+			return toPBSession(ls.sess), nil
+		}
+	}
+	var sess models.Session
+	err := a.s.db.First(&sess, "id = ?", req.Id).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, status.Errorf(codes.NotFound, "no session %q", req.Id)
+	case err != nil:
+		return nil, status.Errorf(codes.Internal, "session not read: %v", err)
+	}
+	return toPBSession(sess), nil
+}
+
+func (a *adminServer) ListPayments(_ context.Context, req *pb.ListPaymentsRequest) (*pb.ListPaymentsResponse, error) {
+	limit, err := pageSize(req.Limit)
+	if err != nil {
+		return nil, err
+	}
+	q, err := afterPage(a.s.db.Order("created_at desc, id desc").Limit(limit+1), "created_at", req.PageToken)
+	if err != nil {
+		return nil, err
+	}
+	if req.PcId != "" {
+		q = q.Where("pc_id = ?", req.PcId)
+	}
+	if req.From > 0 {
+		q = q.Where("created_at >= ?", time.Unix(req.From, 0))
+	}
+	if req.To > 0 {
+		q = q.Where("created_at < ?", time.Unix(req.To, 0))
+	}
+
+	var payments []models.Payment
+	if err := q.Find(&payments).Error; err != nil {
+		return nil, status.Errorf(codes.Internal, "payments not read: %v", err)
+	}
+	resp := &pb.ListPaymentsResponse{}
+	if len(payments) > limit {
+		payments = payments[:limit]
+		last := payments[limit-1]
+		resp.NextPageToken = pageToken(last.CreatedAt, last.ID)
+	}
+	for _, p := range payments {
+		resp.Payments = append(resp.Payments, toPBPayment(p))
+	}
+	return resp, nil
+}
+
+func (a *adminServer) WatchLogs(req *pb.WatchLogsRequest, stream pb.AdminService_WatchLogsServer) error {
+	backlog, ch := a.s.logs.subscribe(int(req.Backlog))
+	defer a.s.logs.unsubscribe(ch)
+
+	for _, e := range backlog {
+		if err := stream.Send(e); err != nil {
+			return err
+		}
+	}
+	ctx := stream.Context()
+	for {
+		select {
+		case e := <-ch:
+			if err := stream.Send(e); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
@@ -0,0 +1,289 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/Mohammad-Mahdi82/NexusOps/pkg/monitor"
+	"github.com/Mohammad-Mahdi82/NexusOps/server/models"
+	"github.com/shopspring/decimal"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+// newTestServer returns a server on its own in-memory database with the default config.
+func newTestServer(t *testing.T) *server {
+	t.Helper()
+	name := strings.NewReplacer("/", "_", " ", "_").Replace(t.Name())
+	db, err := InitDB(fmt.Sprintf("file:%s?mode=memory&cache=shared", name))
+	if err != nil {
+		t.Fatalf("InitDB: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return newServer(defaultConfig(), db, nil, &pb.ClientSettings{}, newLogHub())
+}
+
+func mustCreate(t *testing.T, s *server, value any) {
+	t.Helper()
+	if err := s.db.Create(value).Error; err != nil {
+		t.Fatalf("create %T: %v", value, err)
+	}
+}
+
+func TestEditSession(t *testing.T) {
+	end := time.Now().Add(-time.Hour)
+	ended := func(id string) *models.Session {
+		return &models.Session{
+			ID: id, PcID: "PC-01", GameName: "cs2.exe", RateClass: DefaultRateClass,
+			StartTime: end.Add(-time.Hour), EndTime: end, DurationMinutes: 60, Fee: decimal.NewFromInt(50000),
+		}
+	}
+	ptr := func(v int) *int { return &v }
+	class := func(v string) *string { return &v }
+	fee := decimal.NewFromInt(1234)
+
+	tests := []struct {
+		name    string
+		setup   func(s *server)
+		edit    SessionEdit
+		wantFee string
+		wantErr error
+	}{
+		{
+			name:    "duration re-prices",
+			edit:    SessionEdit{DurationMinutes: ptr(90)},
+			wantFee: "75000",
+		},
+		{
+			name:    "rate class re-prices",
+			edit:    SessionEdit{RateClass: class("vip")},
+			wantFee: "80000",
+		},
+		{
+			name:    "fee overrides the price",
+			edit:    SessionEdit{RateClass: class("vip"), DurationMinutes: ptr(90), Fee: &fee},
+			wantFee: "1234",
+		},
+		{
+			name: "paid session is refused",
+			setup: func(s *server) {
+				s.db.Model(&models.Session{}).Where("id = ?", "s1").Update("paid", true)
+			},
+			edit:    SessionEdit{DurationMinutes: ptr(10)},
+			wantErr: errSessionPaid,
+		},
+		{
+			name: "running session is refused",
+			setup: func(s *server) {
+				s.liveSessions["PC-01"] = &liveSession{sess: *ended("s1")}
+			},
+			edit:    SessionEdit{DurationMinutes: ptr(10)},
+			wantErr: errSessionRunning,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t)
+			s.cfg.Rates = map[string]int64{DefaultRateClass: 50000, "vip": 80000}
+			mustCreate(t, s, ended("s1"))
+			if tt.setup != nil {
+				tt.setup(s)
+			}
+
+			sess, err := s.editSession("s1", tt.edit)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("editSession() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if sess.Fee.String() != tt.wantFee {
+				t.Errorf("fee = %s, want %s", sess.Fee, tt.wantFee)
+			}
+			var stored models.Session
+			s.db.First(&stored, "id = ?", "s1")
+			if !stored.Fee.Equal(sess.Fee) || stored.DurationMinutes != sess.DurationMinutes || stored.RateClass != sess.RateClass {
+				t.Errorf("stored %+v, returned %+v", stored, sess)
+			}
+		})
+	}
+
+	t.Run("unknown session", func(t *testing.T) {
+		s := newTestServer(t)
+		if _, err := s.editSession("nope", SessionEdit{}); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Errorf("editSession() error = %v, want ErrRecordNotFound", err)
+		}
+	})
+}
+
+func TestListSessionsPaging(t *testing.T) {
+	s := newTestServer(t)
+	a := &adminServer{s: s}
+	base := time.Now().Add(-24 * time.Hour).Truncate(time.Second)
+	// s3 and s4 share a start time, so the page boundary has to fall back on the ID
+	starts := map[string]time.Duration{"s1": 0, "s2": time.Minute, "s3": 2 * time.Minute, "s4": 2 * time.Minute, "s5": 3 * time.Minute}
+	for id, offset := range starts {
+		mustCreate(t, s, &models.Session{GameName: id, PcID: "PC-01", StartTime: base.Add(offset), EndTime: base.Add(offset + time.Minute)})
+	}
+	mustCreate(t, s, &models.Session{GameName: "other", PcID: "PC-02", StartTime: base})
+
+	var got []string
+	seen := make(map[string]bool)
+	req := &pb.ListSessionsRequest{PcId: "PC-01", Limit: 2}
+	for page := 0; ; page++ {
+		if page > 5 {
+			t.Fatal("paging never ended")
+		}
+		resp, err := a.ListSessions(context.Background(), req)
+		if err != nil {
+			t.Fatalf("ListSessions: %v", err)
+		}
+		if len(resp.Sessions) > 2 {
+			t.Fatalf("page has %d sessions, limit is 2", len(resp.Sessions))
+		}
+		for _, sess := range resp.Sessions {
+			if seen[sess.Id] {
+				t.Fatalf("session %s (%s) came back twice", sess.Id, sess.GameName)
+			}
+			seen[sess.Id] = true
+			got = append(got, sess.GameName)
+		}
+		if resp.NextPageToken == "" {
+			break
+		}
+		req.PageToken = resp.NextPageToken
+	}
+
+	// IDs are random, so s3 and s4 may come in either order
+	if joined := strings.Join(got, " "); joined != "s5 s4 s3 s2 s1" && joined != "s5 s3 s4 s2 s1" {
+		t.Errorf("sessions = %v, want s5, s4 and s3 in either order, s2, s1", got)
+	}
+
+	_, err := a.ListSessions(context.Background(), &pb.ListSessionsRequest{PageToken: "not a token"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("bad page token: error = %v, want InvalidArgument", err)
+	}
+}
+
+func TestListPaymentsPaging(t *testing.T) {
+	s := newTestServer(t)
+	a := &adminServer{s: s}
+	base := time.Now().Add(-time.Hour).Truncate(time.Second)
+	for i, label := range []string{"p1", "p2", "p3"} {
+		mustCreate(t, s, &models.Payment{Operator: label, PcID: "PC-01", Total: decimal.NewFromInt(1000), CreatedAt: base.Add(time.Duration(i) * time.Minute)})
+	}
+
+	first, err := a.ListPayments(context.Background(), &pb.ListPaymentsRequest{Limit: 2})
+	if err != nil {
+		t.Fatalf("ListPayments: %v", err)
+	}
+	if len(first.Payments) != 2 || first.Payments[0].Operator != "p3" || first.Payments[1].Operator != "p2" || first.NextPageToken == "" {
+		t.Fatalf("first page = %v, next %q", first.Payments, first.NextPageToken)
+	}
+
+	// A payment taken between the two calls must not shift the second page
+	mustCreate(t, s, &models.Payment{Operator: "p4", PcID: "PC-01", Total: decimal.NewFromInt(1000), CreatedAt: base.Add(10 * time.Minute)})
+	second, err := a.ListPayments(context.Background(), &pb.ListPaymentsRequest{Limit: 2, PageToken: first.NextPageToken})
+	if err != nil {
+		t.Fatalf("ListPayments: %v", err)
+	}
+	if len(second.Payments) != 1 || second.Payments[0].Operator != "p1" || second.NextPageToken != "" {
+		t.Errorf("second page = %v, next %q", second.Payments, second.NextPageToken)
+	}
+
+	if _, err := a.ListPayments(context.Background(), &pb.ListPaymentsRequest{Limit: -1}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("negative limit: error = %v, want InvalidArgument", err)
+	}
+}
+
+func TestSendCommandValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      *pb.SendCommandRequest
+		wantCode codes.Code
+	}{
+		{name: "no PC", req: &pb.SendCommandRequest{Action: pb.SendCommandRequest_CLOSE_GAMES}, wantCode: codes.InvalidArgument},
+		{name: "unknown PC", req: &pb.SendCommandRequest{PcId: "PC-99", Action: pb.SendCommandRequest_LOCK}, wantCode: codes.NotFound},
+		{name: "empty message", req: &pb.SendCommandRequest{PcId: "PC-01", Text: "  "}, wantCode: codes.InvalidArgument},
+		{name: "unknown action", req: &pb.SendCommandRequest{PcId: "PC-01", Action: 42}, wantCode: codes.InvalidArgument},
+		{name: "offline PC", req: &pb.SendCommandRequest{PcId: "PC-01", Action: pb.SendCommandRequest_CLOSE_GAMES}},
+		{name: "lock offline PC", req: &pb.SendCommandRequest{PcId: "PC-01", Action: pb.SendCommandRequest_LOCK}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t)
+			a := &adminServer{s: s}
+			known := &models.Station{ID: "PC-01"}
+			mustCreate(t, s, known)
+			s.stations[known.ID] = known
+
+			resp, err := a.SendCommand(context.Background(), tt.req)
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("SendCommand() error = %v, want code %v", err, tt.wantCode)
+			}
+			if err == nil && resp.Delivered {
+				t.Error("delivered to a PC that isn't connected")
+			}
+
+			var count int64
+			s.db.Model(&models.Station{}).Count(&count)
+			if count != 1 {
+				t.Errorf("%d station rows, want 1: a rejected command must not add one", count)
+			}
+		})
+	}
+}
+
+func TestRecordPaymentDiscount(t *testing.T) {
+	tests := []struct {
+		name      string
+		discount  string
+		wantTotal string
+		wantCode  codes.Code
+	}{
+		{name: "no discount", wantTotal: "109000"},
+		{name: "discount before tax", discount: "10000", wantTotal: "98100"},
+		{name: "whole subtotal", discount: "100000", wantTotal: "0"},
+		{name: "more than the subtotal", discount: "100001", wantCode: codes.InvalidArgument},
+		{name: "negative", discount: "-5", wantCode: codes.InvalidArgument},
+		{name: "not a number", discount: "ten", wantCode: codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t)
+			s.cfg.Receipt.TaxPercent = 9
+			a := &adminServer{s: s}
+			end := time.Now().Add(-time.Hour)
+			for i, fee := range []int64{60000, 40000} {
+				mustCreate(t, s, &models.Session{
+					ID: fmt.Sprint("s", i), PcID: "PC-01", StartTime: end.Add(-time.Hour), EndTime: end, Fee: decimal.NewFromInt(fee),
+				})
+			}
+
+			resp, err := a.RecordPayment(context.Background(), &pb.RecordPaymentRequest{PcId: "PC-01", Method: "cash", Discount: tt.discount})
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("RecordPayment() error = %v, want code %v", err, tt.wantCode)
+			}
+			var unpaid int64
+			s.db.Model(&models.Session{}).Where("paid = ?", false).Count(&unpaid)
+			if err != nil {
+				if unpaid != 2 {
+					t.Errorf("a refused payment settled %d sessions", 2-unpaid)
+				}
+				return
+			}
+			if resp.Payment.Total != tt.wantTotal || unpaid != 0 {
+				t.Errorf("total %s with %d sessions unpaid, want %s with none", resp.Payment.Total, unpaid, tt.wantTotal)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"net"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+type operatorKey struct{}
+
+// operatorFor works out which operator an authorization header belongs to. Tokens are
+// checked when configured; otherwise only callers on this machine get in.
+func (s *server) operatorFor(authorization, remoteAddr string) (string, error) {
+	if len(s.cfg.Admin.Tokens) == 0 {
+		if !isLoopback(remoteAddr) {
+			return "", status.Error(codes.PermissionDenied, "admin API is localhost-only until admin tokens are configured")
+		}
+		return s.cfg.Operator, nil
+	}
+
+	token := strings.TrimSpace(strings.TrimPrefix(authorization, "Bearer "))
+	if token == "" {
+		return "", status.Error(codes.Unauthenticated, "missing admin token")
+	}
+	for name, want := range s.cfg.Admin.Tokens {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(want)) == 1 {
+			return name, nil
+		}
+	}
+	return "", status.Error(codes.Unauthenticated, "invalid admin token")
+}
+
+// authenticate puts the calling operator in a gRPC call's context.
+func (s *server) authenticate(ctx context.Context) (context.Context, error) {
+	var authorization, remoteAddr string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get("authorization"); len(v) > 0 {
+			authorization = v[0]
+		}
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		remoteAddr = p.Addr.String()
+	}
+	operator, err := s.operatorFor(authorization, remoteAddr)
+	if err != nil {
+		return nil, err
+	}
+	return withOperator(ctx, operator), nil
+}
+
+func withOperator(ctx context.Context, operator string) context.Context {
+	return context.WithValue(ctx, operatorKey{}, operator)
+}
+
+func isLoopback(remoteAddr string) bool {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return false
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// operatorFrom is the operator authenticate put in the context.
+func operatorFrom(ctx context.Context) string {
+	name, _ := ctx.Value(operatorKey{}).(string)
+	return name
+}
+
+func (s *server) unaryAuth(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := s.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (s *server) streamAuth(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := s.authenticate(ss.Context())
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authedStream{ServerStream: ss, ctx: ctx})
+}
+
+// authedStream hands the operator's context to stream handlers.
+type authedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (a *authedStream) Context() context.Context {
+	return a.ctx
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestOperatorFor(t *testing.T) {
+	tests := []struct {
+		name          string
+		tokens        map[string]string
+		authorization string
+		remoteAddr    string
+		want          string
+		wantCode      codes.Code
+	}{
+		{name: "loopback without tokens", remoteAddr: "127.0.0.1:51000", want: "Admin"},
+		{name: "IPv6 loopback without tokens", remoteAddr: "[::1]:51000", want: "Admin"},
+		{name: "LAN without tokens", remoteAddr: "192.168.1.20:51000", wantCode: codes.PermissionDenied},
+		{name: "missing token", tokens: map[string]string{"sara": "s3cret"}, remoteAddr: "127.0.0.1:51000", wantCode: codes.Unauthenticated},
+		{name: "bad token", tokens: map[string]string{"sara": "s3cret"}, authorization: "Bearer guess", remoteAddr: "192.168.1.20:51000", wantCode: codes.Unauthenticated},
+		{name: "valid token", tokens: map[string]string{"sara": "s3cret", "ali": "other"}, authorization: "Bearer s3cret", remoteAddr: "192.168.1.20:51000", want: "sara"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &server{cfg: defaultConfig()}
+			s.cfg.Operator = "Admin"
+			s.cfg.Admin.Tokens = tt.tokens
+
+			got, err := s.operatorFor(tt.authorization, tt.remoteAddr)
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("operatorFor() error = %v, want code %v", err, tt.wantCode)
+			}
+			if got != tt.want {
+				t.Errorf("operatorFor() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"log/slog"
+	"sort"
+	"strings"
+
+	pb "github.com/Mohammad-Mahdi82/NexusOps/pkg/monitor"
+	"github.com/Mohammad-Mahdi82/NexusOps/server/models"
+	"github.com/shopspring/decimal"
+)
+
+// knownGames seeds an empty catalog so the common titles show up named on day one.
+var knownGames = []models.Game{
+	{Executable: "cs2.exe", Title: "Counter-Strike 2", Genre: "FPS"},
+	{Executable: "dota2.exe", Title: "Dota 2", Genre: "MOBA"},
+	{Executable: "valorant-win64-shipping.exe", Title: "VALORANT", Genre: "FPS"},
+	{Executable: "fortniteclient-win64-shipping.exe", Title: "Fortnite", Genre: "Battle Royale"},
+	{Executable: "r5apex.exe", Title: "Apex Legends", Genre: "Battle Royale"},
+	{Executable: "league of legends.exe", Title: "League of Legends", Genre: "MOBA"},
+	{Executable: "gta5.exe", Title: "Grand Theft Auto V", Genre: "Action"},
+	{Executable: "rocketleague.exe", Title: "Rocket League", Genre: "Sports"},
+	{Executable: "pubg-win64-shipping.exe", Title: "PUBG: Battlegrounds", Genre: "Battle Royale"},
+}
+
+// loadCatalog reads every known executable into memory, seeding the defaults on first run.
+func (s *server) loadCatalog() {
+	var count int64
+	s.db.Model(&models.Game{}).Count(&count)
+	if count == 0 {
+		for _, g := range knownGames {
+			g.RateClass = DefaultRateClass
+			g.Classified = true
+			s.db.Create(&g)
+		}
+	}
+
+	var games []models.Game
+	s.db.Find(&games)
+	for i := range games {
+		s.catalog[games[i].Executable] = &games[i]
+	}
+}
+
+// isPathKey tells catalog entries keyed by full image path from those keyed by name.
+func isPathKey(key string) bool {
+	return strings.ContainsAny(key, `/\`)
+}
+
+// lookupGame finds the catalog entry for a process: its full path when the client sent
+// one, so two different games both shipping a "game.exe" can be told apart, and otherwise
+// its name. Callers must hold s.mu.
+func (s *server) lookupGame(exe, path string) (*models.Game, bool) {
+	if path != "" {
+		if g, ok := s.catalog[strings.ToLower(path)]; ok {
+			return g, true
+		}
+	}
+	g, ok := s.catalog[strings.ToLower(exe)]
+	return g, ok
+}
+
+// gameFor resolves an executable to its catalog entry, queueing unknown ones for the
+// operator under their path when known. Callers must hold s.mu.
+func (s *server) gameFor(exe, path string) *models.Game {
+	if g, ok := s.lookupGame(exe, path); ok {
+		return g
+	}
+
+	key := strings.ToLower(exe)
+	if path != "" {
+		key = strings.ToLower(path)
+	}
+	g := &models.Game{
+		Executable: key,
+		Title:      exe,
+		RateClass:  DefaultRateClass,
+		Classified: false,
+	}
+	if err := s.db.Create(g).Error; err != nil {
+		slog.Error("Could not queue game for classification", "exe", key, "err", err)
+	}
+	s.catalog[key] = g
+	s.emitCatalog()
+	return g
+}
+
+// gamePath is the image path of the game a PC reported under this name, preferring the
+// focused process, or empty when the client didn't send one. Callers must hold s.mu.
+func (s *server) gamePath(pcID, exe string) string {
+	var path string
+	for _, g := range s.pcGames[pcID] {
+		if !strings.EqualFold(g.Name, exe) || g.Path == "" {
+			continue
+		}
+		if g.Focused {
+			return g.Path
+		}
+		if path == "" {
+			path = g.Path
+		}
+	}
+	return path
+}
+
+// classifyGame names an executable and re-labels the unpaid sessions already running it.
+// With notGame the executable is pushed to clients as ignored, so they stop reporting it.
+func (s *server) classifyGame(exe, title, genre, rateClass string, notGame bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := strings.ToLower(exe)
+	g, ok := s.catalog[key]
+	if !ok {
+		g = &models.Game{Executable: key}
+	}
+	g.Title = title
+	g.Genre = genre
+	g.RateClass = rateClass
+	g.Classified = true
+	g.NotGame = notGame
+
+	if err := s.db.Save(g).Error; err != nil {
+		return err
+	}
+	s.catalog[key] = g
+	s.rebuildProcessRules()
+	for _, ls := range s.liveSessions {
+		if entry, _ := s.lookupGame(ls.sess.GameName, ls.sess.GamePath); entry == g {
+			ls.sess.GameTitle, ls.sess.RateClass = title, rateClass
+		}
+	}
+
+	// A name entry only covers the sessions no path entry claims
+	var unpaid []models.Session
+	err := s.db.Where("paid = ? AND (lower(game_name) = ? OR lower(game_path) = ?)", false, key, key).Find(&unpaid).Error
+	var ids []string
+	for _, sess := range unpaid {
+		if entry, _ := s.lookupGame(sess.GameName, sess.GamePath); entry == g {
+			ids = append(ids, sess.ID)
+		}
+	}
+	if err == nil && len(ids) > 0 {
+		err = s.db.Model(&models.Session{}).Where("id IN ?", ids).
+			Updates(map[string]interface{}{"game_title": title, "rate_class": rateClass}).Error
+	}
+	s.emitCatalog()
+	// Any connected PC may have an unpaid session of this game
+	for _, pcID := range s.knownStations(true) {
+		s.emit(pb.Event_STATION_UPDATED, pcID, nil)
+	}
+	return err
+}
+
+// rebuildProcessRules folds the classified catalog into the rules pushed to clients.
+// Callers must hold s.mu.
+func (s *server) rebuildProcessRules() {
+	var games []*models.Game
+	for _, g := range s.catalog {
+		if g.Classified {
+			games = append(games, g)
+		}
+	}
+	// Path entries first, like lookupGame, and a stable order so the version hash only
+	// changes with the catalog
+	sort.Slice(games, func(i, j int) bool {
+		if pi, pj := isPathKey(games[i].Executable), isPathKey(games[j].Executable); pi != pj {
+			return pi
+		}
+		return games[i].Executable < games[j].Executable
+	})
+
+	set, err := buildProcessRules(s.cfg.ProcessRules, games)
+	if err != nil {
+		slog.Error("Process rules not updated", "err", err)
+		return
+	}
+	s.processRules = set
+}
+
+// pendingGames lists executables nobody has classified yet, oldest first.
+func (s *server) pendingGames() []models.Game {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var pending []models.Game
+	for _, g := range s.catalog {
+		if !g.Classified {
+			pending = append(pending, *g)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].CreatedAt.Before(pending[j].CreatedAt) })
+	return pending
+}
+
+// rateClasses returns the configured class names with the default first.
+func (s *server) rateClasses() []string {
+	classes := []string{DefaultRateClass}
+	for class := range s.cfg.Rates {
+		if class != DefaultRateClass {
+			classes = append(classes, class)
+		}
+	}
+	sort.Strings(classes[1:])
+	return classes
+}
+
+func (s *server) hourlyRate(rateClass string) decimal.Decimal {
+	if rate, ok := s.cfg.Rates[rateClass]; ok {
+		return decimal.NewFromInt(rate)
+	}
+	if rate, ok := s.cfg.Rates[DefaultRateClass]; ok {
+		return decimal.NewFromInt(rate)
+	}
+	return decimal.NewFromInt(HourlyRate)
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	pb "github.com/Mohammad-Mahdi82/NexusOps/pkg/monitor"
+	"github.com/Mohammad-Mahdi82/NexusOps/server/models"
+)
+
+func TestCatalogKeysOnPath(t *testing.T) {
+	s := newTestServer(t)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.loadCatalog()
+
+	// A built-in name entry still covers the game wherever it is installed
+	if g := s.gameFor("cs2.exe", `D:\Steam\cs2\cs2.exe`); g.Executable != "cs2.exe" || !g.Classified {
+		t.Errorf("cs2 resolved to %+v", g)
+	}
+
+	racer := s.gameFor("Game.exe", `C:\Games\Racer\Game.exe`)
+	shooter := s.gameFor("Game.exe", `C:\Games\Shooter\Game.exe`)
+	if racer == shooter || racer.Executable != `c:\games\racer\game.exe` || racer.Classified {
+		t.Fatalf("two game.exe resolved to %+v and %+v", racer, shooter)
+	}
+	if g := s.gameFor("game.exe", `c:\games\racer\GAME.EXE`); g != racer {
+		t.Errorf("same path resolved to a new entry %+v", g)
+	}
+	if g := s.gameFor("game.exe", ""); g == racer || g == shooter || g.Executable != "game.exe" {
+		t.Errorf("no path resolved to %+v", g)
+	}
+
+	var queued int64
+	s.db.Model(&models.Game{}).Where("classified = ?", false).Count(&queued)
+	if queued != 3 {
+		t.Errorf("%d games queued, want 3", queued)
+	}
+}
+
+func TestClassifyGameByPath(t *testing.T) {
+	s := newTestServer(t)
+	s.cfg.Rates = map[string]int64{DefaultRateClass: 50000, "vip": 80000}
+	end := time.Now().Add(-time.Hour)
+	for _, sess := range []models.Session{
+		{ID: "racer", PcID: "PC-01", GameName: "game.exe", GamePath: `C:\Games\Racer\game.exe`, StartTime: end, EndTime: end},
+		{ID: "shooter", PcID: "PC-02", GameName: "game.exe", GamePath: `C:\Games\Shooter\game.exe`, StartTime: end, EndTime: end},
+	} {
+		mustCreate(t, s, &sess)
+	}
+	s.mu.Lock()
+	s.gameFor("game.exe", `C:\Games\Racer\game.exe`)
+	s.gameFor("game.exe", `C:\Games\Shooter\game.exe`)
+	s.mu.Unlock()
+
+	if err := s.classifyGame(`c:\games\racer\game.exe`, "Racer", "Racing", "vip", false); err != nil {
+		t.Fatal(err)
+	}
+
+	var racer, shooter models.Session
+	s.db.First(&racer, "id = ?", "racer")
+	s.db.First(&shooter, "id = ?", "shooter")
+	if racer.GameTitle != "Racer" || racer.RateClass != "vip" {
+		t.Errorf("racer session = %q at %q", racer.GameTitle, racer.RateClass)
+	}
+	if shooter.GameTitle == "Racer" || shooter.RateClass == "vip" {
+		t.Errorf("shooter session was relabelled: %q at %q", shooter.GameTitle, shooter.RateClass)
+	}
+
+	var pathRule *pb.ProcessRule
+	for _, r := range s.processRules.Rules {
+		if r.Pattern == `c:\games\racer\game.exe` {
+			pathRule = r
+		}
+	}
+	if pathRule == nil || pathRule.Match != pb.ProcessRule_PATH_GLOB || pathRule.Action != pb.ProcessRule_TRACK {
+		t.Errorf("rule for the classified path = %v", pathRule)
+	}
+}
+
+func TestClassifyNotAGame(t *testing.T) {
+	s := newTestServer(t)
+	s.mu.Lock()
+	s.loadCatalog()
+	s.gameFor("ToolLauncher.exe", "")
+	s.gameFor("game.exe", `C:\Games\Racer\game.exe`)
+	s.mu.Unlock()
+
+	a := &adminServer{s: s}
+	if _, err := a.ClassifyGame(context.Background(), &pb.ClassifyGameRequest{Executable: "toollauncher.exe", NotGame: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.classifyGame(`c:\games\racer\game.exe`, "Racer", "Racing", DefaultRateClass, false); err != nil {
+		t.Fatal(err)
+	}
+	if pending := s.pendingGames(); len(pending) != 0 {
+		t.Errorf("still pending: %v", pending)
+	}
+
+	var stored models.Game
+	s.db.First(&stored, "executable = ?", "toollauncher.exe")
+	if !stored.Classified || !stored.NotGame {
+		t.Errorf("stored entry = %+v, want classified as not a game", stored)
+	}
+
+	actions := make(map[string]pb.ProcessRule_Action)
+	var order []string
+	for _, r := range s.processRules.Rules {
+		if _, seen := actions[r.Pattern]; !seen {
+			actions[r.Pattern] = r.Action
+			order = append(order, r.Pattern)
+		}
+	}
+	if actions["toollauncher.exe"] != pb.ProcessRule_IGNORE || actions["cs2.exe"] != pb.ProcessRule_TRACK {
+		t.Errorf("rules = %v", s.processRules.Rules)
+	}
+	// Like lookupGame, a path entry has to match before any name entry
+	if i := slices.Index(order, `c:\games\racer\game.exe`); i < 0 || i > slices.Index(order, "cs2.exe") {
+		t.Errorf("path rule at %d in %v", i, order)
+	}
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	pb "github.com/Mohammad-Mahdi82/NexusOps/pkg/monitor"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// adminFlags adds the flags every subcommand that talks to the daemon takes.
+func adminFlags(fs *flag.FlagSet) (addr, token *string) {
+	addr = fs.String("addr", "localhost:50052", "Admin address of the NexusOps daemon")
+	token = fs.String("token", os.Getenv("NEXUS_ADMIN_TOKEN"), "Admin token, if the daemon has tokens configured")
+	return addr, token
+}
+
+// dialAdmin connects to the daemon's AdminService. Nothing is sent until the first call.
+func dialAdmin(addr, token string) (*grpc.ClientConn, error) {
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	if token != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(bearerToken(token)))
+	}
+	return grpc.NewClient(addr, opts...)
+}
+
+// bearerToken sends the admin token with every call.
+type bearerToken string
+
+func (t bearerToken) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + string(t)}, nil
+}
+
+// RequireTransportSecurity is false because the admin API is plain gRPC on the cafe LAN.
+func (t bearerToken) RequireTransportSecurity() bool {
+	return false
+}
+
+// dayRange turns -from and -to days into [from, to) in local time. -to includes the
+// whole day. Either one may be empty, leaving that end zero.
+func dayRange(fromDay, toDay string) (from, to time.Time, err error) {
+	if fromDay != "" {
+		if from, err = time.ParseInLocation("2006-01-02", fromDay, time.Local); err != nil {
+			return from, to, fmt.Errorf("-from: %w", err)
+		}
+	}
+	if toDay != "" {
+		if to, err = time.ParseInLocation("2006-01-02", toDay, time.Local); err != nil {
+			return from, to, fmt.Errorf("-to: %w", err)
+		}
+		to = to.AddDate(0, 0, 1)
+	}
+	return from, to, nil
+}
+
+func unixOrZero(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
+var reportPeriods = map[string]pb.ReportRequest_Period{
+	"day":   pb.ReportRequest_DAY,
+	"week":  pb.ReportRequest_WEEK,
+	"month": pb.ReportRequest_MONTH,
+}
+
+// runReport implements `nexus-server report`.
+func runReport(args []string) error {
+	fs := flag.NewFlagSet("report", flag.ExitOnError)
+	addr, token := adminFlags(fs)
+	fromFlag := fs.String("from", "", "First day, YYYY-MM-DD (default 30 days before -to)")
+	toFlag := fs.String("to", "", "Last day, YYYY-MM-DD, included (default today)")
+	by := fs.String("by", "day", "Group revenue by day, week or month")
+	format := fs.String("format", "text", "Output as text, csv or json")
+	table := fs.String("table", "period", "Which table csv writes: period, pc, game, operator or hours")
+	fs.Parse(args)
+
+	req := &pb.ReportRequest{}
+	period, ok := reportPeriods[*by]
+	if !ok {
+		return fmt.Errorf("-by must be day, week or month, not %q", *by)
+	}
+	req.Period = period
+	from, to, err := dayRange(*fromFlag, *toFlag)
+	if err != nil {
+		return err
+	}
+	req.From, req.To = unixOrZero(from), unixOrZero(to)
+
+	conn, err := dialAdmin(*addr, *token)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	rep, err := pb.NewAdminServiceClient(conn).GetReport(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	switch *format {
+	case "json":
+		data, err := protojson.MarshalOptions{Multiline: true, EmitUnpopulated: true}.Marshal(rep)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(os.Stdout, string(data))
+		return err
+	case "csv":
+		return writeReportCSV(os.Stdout, rep, *table)
+	case "text":
+		return writeReportText(os.Stdout, rep)
+	}
+	return fmt.Errorf("-format must be text, csv or json, not %q", *format)
+}
+
+// writeReportCSV writes one of the report's tables, with a header row.
+func writeReportCSV(w io.Writer, rep *pb.Report, table string) error {
+	cw := csv.NewWriter(w)
+	if table == "hours" {
+		cw.Write([]string{"weekday", "hour", "minutes", "utilization"})
+		for _, h := range rep.Hours {
+			cw.Write([]string{
+				time.Weekday(h.Weekday).String(), strconv.Itoa(int(h.Hour)),
+				strconv.Itoa(int(h.Minutes)), strconv.FormatFloat(h.Utilization, 'f', 4, 64),
+			})
+		}
+		cw.Flush()
+		return cw.Error()
+	}
+
+	rows, ok := map[string][]*pb.ReportRow{
+		"period": rep.ByPeriod, "pc": rep.ByPc, "game": rep.ByGame, "operator": rep.ByOperator,
+	}[table]
+	if !ok {
+		return fmt.Errorf("-table must be period, pc, game, operator or hours, not %q", table)
+	}
+	cw.Write([]string{table, "revenue", "payments", "sessions", "minutes"})
+	for _, r := range rows {
+		cw.Write([]string{
+			r.Key, r.Revenue, strconv.Itoa(int(r.Payments)),
+			strconv.Itoa(int(r.Sessions)), strconv.Itoa(int(r.Minutes)),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeReportText prints the whole report as aligned tables for reading in a terminal.
+func writeReportText(w io.Writer, rep *pb.Report) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	from, to := time.Unix(rep.From, 0), time.Unix(rep.To, 0)
+	fmt.Fprintf(tw, "From\t%s\nTo\t%s\n", from.Format("2006-01-02 15:04"), to.Format("2006-01-02 15:04"))
+	fmt.Fprintf(tw, "Revenue\t%s\nPayments\t%d\nSessions\t%d\nBilled minutes\t%d\nAverage session\t%.0f min\n",
+		money(rep.Revenue), rep.Payments, rep.Sessions, rep.Minutes, rep.AverageSessionMinutes)
+
+	for _, t := range []struct {
+		title string
+		rows  []*pb.ReportRow
+	}{
+		{"PERIOD", rep.ByPeriod}, {"PC", rep.ByPc}, {"GAME", rep.ByGame}, {"OPERATOR", rep.ByOperator},
+	} {
+		fmt.Fprintf(tw, "\n%s\tREVENUE\tPAYMENTS\tSESSIONS\tMINUTES\n", t.title)
+		for _, r := range t.rows {
+			fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%d\n", r.Key, money(r.Revenue), r.Payments, r.Sessions, r.Minutes)
+		}
+	}
+
+	fmt.Fprintf(tw, "\nUTILIZATION %%")
+	for hour := range 24 {
+		fmt.Fprintf(tw, "\t%02d", hour)
+	}
+	fmt.Fprintln(tw)
+	for day := range 7 {
+		fmt.Fprint(tw, time.Weekday(day).String()[:3])
+		for _, h := range rep.Hours[day*24 : day*24+24] {
+			fmt.Fprintf(tw, "\t%.0f", h.Utilization*100)
+		}
+		fmt.Fprintln(tw)
+	}
+	return tw.Flush()
+}
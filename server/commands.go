@@ -0,0 +1,106 @@
+package main
+
+import (
+	"log/slog"
+	"time"
+
+	pb "github.com/Mohammad-Mahdi82/NexusOps/pkg/monitor"
+)
+
+// outboxSize is how many commands may queue for a PC before new ones are dropped.
+const outboxSize = 16
+
+// outgoing is a command waiting in a PC's outbox, stamped so delivery latency can be measured.
+type outgoing struct {
+	resp   *pb.CommandResponse
+	queued time.Time
+}
+
+// commandFor builds what the server currently wants from a PC: its lock state, and its
+// games closed if a kill is pending. Callers must hold s.mu.
+func (s *server) commandFor(pcID string) *pb.CommandResponse {
+	shouldKill := s.killSignals[pcID]
+	if shouldKill {
+		s.killSignals[pcID] = false
+	}
+
+	locked := s.pcLocked[pcID]
+	resp := &pb.CommandResponse{CloseActiveGame: shouldKill, Locked: locked}
+	if locked {
+		resp.LockMessage = s.lockMessage()
+	}
+	if shouldKill {
+		resp.CloseGames = s.gamesToClose(pcID)
+		resp.CloseTimeoutSeconds = int32(s.cfg.CloseTimeout)
+	}
+	return resp
+}
+
+// sendCommand delivers a PC's pending command now instead of with its next heartbeat.
+// It reports false when the PC isn't connected or its queue is full.
+func (s *server) sendCommand(pcID string) bool {
+	return s.sendNotice(pcID, "")
+}
+
+// sendNotice is sendCommand with a message for the customer.
+func (s *server) sendNotice(pcID, notice string) bool {
+	s.mu.Lock()
+	out, ok := s.outbox[pcID]
+	if !ok {
+		s.mu.Unlock()
+		return false
+	}
+	if locked, changed := s.applyLock(pcID); changed {
+		if locked {
+			s.endSession(pcID)
+		}
+		s.emit(pb.Event_STATION_UPDATED, pcID, nil)
+	}
+	resp := s.commandFor(pcID)
+	resp.Notice = notice
+	s.mu.Unlock()
+
+	select {
+	case out <- outgoing{resp, time.Now()}:
+		return true
+	default:
+		slog.Warn("Command queue full, dropping command", "pc", pcID)
+		return false
+	}
+}
+
+// closeGames asks a connected PC to close its games, as if it had just been settled.
+func (s *server) closeGames(pcID string) bool {
+	s.mu.Lock()
+	_, connected := s.outbox[pcID]
+	if connected {
+		s.killSignals[pcID] = true
+	}
+	s.mu.Unlock()
+	return connected && s.sendCommand(pcID)
+}
+
+// endSession stops billing a connected PC right away, e.g. when it gets locked.
+// Callers must hold s.mu.
+func (s *server) endSession(pcID string) {
+	oldGame := s.pcStates[pcID]
+	s.handleGameTransition(pcID, oldGame, "Idle")
+	s.pcStates[pcID] = "Idle"
+	s.emitGame(pcID, oldGame, "Idle")
+}
+
+// expiredStations lists connected PCs whose timed access just ran out. Callers must hold s.mu.
+func (s *server) expiredStations() []string {
+	var expired []string
+	for pcID := range s.outbox {
+		if s.pcLocked[pcID] {
+			continue
+		}
+		if locked, _ := s.applyLock(pcID); locked {
+			s.endSession(pcID)
+			s.emit(pb.Event_STATION_UPDATED, pcID, nil)
+			expired = append(expired, pcID)
+		}
+	}
+	return expired
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/user"
+	"path"
+	"path/filepath"
+	"strconv"
+
+	pb "github.com/Mohammad-Mahdi82/NexusOps/pkg/monitor"
+)
+
+// Config holds the server settings loaded from nexus_ops.json.
+// Every field has a sane default so the file is optional.
+type Config struct {
+	Operator      string              `json:"operator"`
+	Rates         map[string]int64    `json:"rates"`                 // Hourly rate per game rate class
+	BillingPolicy string              `json:"billing_policy"`        // "running" or "focused"
+	CloseTimeout  int                 `json:"close_timeout_seconds"` // Grace period before a closing game is killed
+	DBFlush       int                 `json:"db_flush_seconds"`      // How often running sessions are written to the DB
+	Idle          IdleConfig          `json:"idle"`                  // Default policy, stations can override it
+	Lock          LockConfig          `json:"lock"`                  // Client lock screen
+	ProcessRules  []ProcessRuleConfig `json:"process_rules"`         // Pushed to every client, first match wins
+	Locale        LocaleConfig        `json:"locale"`
+	Receipt       ReceiptConfig       `json:"receipt"`
+	Printer       PrinterConfig       `json:"printer"`
+	Client        ClientConfig        `json:"client"` // Pushed to every Sentry, overriding its sentry.json
+	Discovery     DiscoveryConfig     `json:"discovery"`
+	Network       NetworkConfig       `json:"network"`
+	Admin         AdminConfig         `json:"admin"`
+	DBPath        string              `json:"db_path"` // Relative paths are next to the executable
+	Log           LogConfig           `json:"log"`
+}
+
+// NetworkConfig is shared by the gRPC listener, the mDNS beacon and the firewall rules.
+type NetworkConfig struct {
+	Listen            string   `json:"listen"`             // gRPC address, e.g. ":50051" or "192.168.1.10:50051"
+	AdminListen       string   `json:"admin_listen"`       // Where consoles attach, localhost only by default
+	HTTPListen        string   `json:"http_listen"`        // Web dashboard, localhost only by default, empty to turn it off
+	MetricsListen     string   `json:"metrics_listen"`     // Prometheus /metrics, localhost only by default
+	AdvertisedPort    int      `json:"advertised_port"`    // Port announced to clients, defaults to the listen port
+	Interfaces        []string `json:"interfaces"`         // Advertise only on these (globs), empty for all
+	ExcludeInterfaces []string `json:"exclude_interfaces"` // Never advertise on these (globs)
+}
+
+// ListenPort is the port part of Listen.
+func (n NetworkConfig) ListenPort() (int, error) {
+	_, port, err := net.SplitHostPort(n.Listen)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(port)
+}
+
+// BeaconPort is the port clients should dial.
+func (n NetworkConfig) BeaconPort() int {
+	if n.AdvertisedPort > 0 {
+		return n.AdvertisedPort
+	}
+	port, _ := n.ListenPort()
+	return port
+}
+
+// AdminConfig guards the admin API. Each operator gets a token, and payments record who
+// took them. With no tokens the API only answers localhost, as the configured operator.
+type AdminConfig struct {
+	Tokens map[string]string `json:"tokens"` // Operator name -> token
+}
+
+// DiscoveryConfig is advertised in the beacon's TXT record. Sentries try primaries
+// before standbys and lower priorities first, and fail over down that list.
+// Site keeps cafes sharing a LAN apart: Sentries only accept servers of their own site.
+type DiscoveryConfig struct {
+	Site     string `json:"site"`
+	Role     string `json:"role"` // "primary" or "standby"
+	Priority int    `json:"priority"`
+}
+
+// ClientConfig is the fleet-wide part of the Sentry settings. Zero leaves a setting to each PC.
+type ClientConfig struct {
+	HeartbeatSeconds        int `json:"heartbeat_seconds"`
+	KeepaliveSeconds        int `json:"keepalive_seconds"`
+	DiscoveryTimeoutSeconds int `json:"discovery_timeout_seconds"`
+}
+
+// IdleConfig escalates as a PC goes without input. A zero value disables that step.
+type IdleConfig struct {
+	WarnMinutes  int `json:"warn_minutes"`  // Show the customer a notice
+	PauseMinutes int `json:"pause_minutes"` // Stop billing until input resumes
+	LockMinutes  int `json:"lock_minutes"`  // End the session and close the game
+}
+
+type LocaleConfig struct {
+	Language      string `json:"language"`       // "en" or "fa"
+	Calendar      string `json:"calendar"`       // "gregorian" or "jalali"
+	PersianDigits bool   `json:"persian_digits"` // Render ۰-۹ instead of 0-9
+}
+
+type ReceiptConfig struct {
+	CafeName       string   `json:"cafe_name"`
+	Footer         string   `json:"footer"`
+	TaxPercent     float64  `json:"tax_percent"`
+	PaymentMethods []string `json:"payment_methods"` // Offered in the pay dialog, first one is the default
+}
+
+type PrinterConfig struct {
+	Backend   string `json:"backend"`    // "none", "escpos" or "text"
+	Device    string `json:"device"`     // ESC/POS device or file path, e.g. /dev/usb/lp0
+	Address   string `json:"address"`    // ESC/POS network printer, e.g. 192.168.1.50:9100
+	OutputDir string `json:"output_dir"` // Where the text backend drops receipts
+	Width     int    `json:"width"`      // Characters per line (32 for 58mm, 48 for 80mm paper)
+}
+
+func defaultConfig() Config {
+	operator := "operator"
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		operator = u.Username
+	}
+	return Config{
+		Operator:      operator,
+		Rates:         map[string]int64{DefaultRateClass: HourlyRate},
+		ProcessRules:  defaultProcessRules(),
+		BillingPolicy: BillRunning,
+		CloseTimeout:  10,
+		DBFlush:       30,
+		Discovery: DiscoveryConfig{
+			Role: "primary",
+		},
+		Network: NetworkConfig{
+			Listen:        ":50051",
+			AdminListen:   "127.0.0.1:50052",
+			HTTPListen:    "127.0.0.1:8080",
+			MetricsListen: "127.0.0.1:9090",
+			// Container and VM bridges aren't where the cafe PCs are
+			ExcludeInterfaces: []string{"docker*", "br-*", "veth*", "virbr*", "vEthernet*"},
+		},
+		DBPath: "nexus_ops.db",
+		Log: LogConfig{
+			Path:       "nexus_ops.log",
+			Level:      "info",
+			MaxSizeMB:  10,
+			MaxBackups: 5,
+			MaxAgeDays: 30,
+		},
+		Idle: IdleConfig{
+			WarnMinutes:  10,
+			PauseMinutes: 15,
+		},
+		Locale: LocaleConfig{
+			Language: "en",
+			Calendar: "gregorian",
+		},
+		Receipt: ReceiptConfig{
+			CafeName:       "NexusOps Game Cafe",
+			Footer:         "Thank you for playing!",
+			PaymentMethods: []string{"cash", "card"},
+		},
+		Printer: PrinterConfig{
+			Backend:   "none",
+			OutputDir: "receipts",
+			Width:     32,
+		},
+	}
+}
+
+// LoadConfig reads nexus_ops.json from the executable's directory, falling back to defaults.
+func LoadConfig() (Config, error) {
+	cfg := defaultConfig()
+
+	data, err := os.ReadFile(configPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, err
+	}
+	if cfg.Client.HeartbeatSeconds < 0 || cfg.Client.KeepaliveSeconds < 0 || cfg.Client.DiscoveryTimeoutSeconds < 0 {
+		return cfg, errors.New("client settings can't be negative")
+	}
+	if cfg.Discovery.Role != "primary" && cfg.Discovery.Role != "standby" {
+		return cfg, errors.New(`discovery.role must be "primary" or "standby"`)
+	}
+	if port, err := cfg.Network.ListenPort(); err != nil || port <= 0 {
+		return cfg, fmt.Errorf("network.listen %q needs a host:port, e.g. \":50051\"", cfg.Network.Listen)
+	}
+	if cfg.Network.AdvertisedPort < 0 {
+		return cfg, errors.New("network.advertised_port can't be negative")
+	}
+	for _, pattern := range append(cfg.Network.Interfaces, cfg.Network.ExcludeInterfaces...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return cfg, fmt.Errorf("network interface pattern %q: %w", pattern, err)
+		}
+	}
+	if cfg.DBPath == "" {
+		return cfg, errors.New("db_path can't be empty")
+	}
+	if cfg.DBFlush <= 0 {
+		return cfg, errors.New("db_flush_seconds must be positive")
+	}
+	return cfg, nil
+}
+
+// buildClientSettings turns the client section into the message pushed to Sentries.
+func buildClientSettings(cfg ClientConfig) (*pb.ClientSettings, error) {
+	set := &pb.ClientSettings{
+		HeartbeatIntervalSeconds: uint32(cfg.HeartbeatSeconds),
+		KeepaliveSeconds:         uint32(cfg.KeepaliveSeconds),
+		DiscoveryTimeoutSeconds:  uint32(cfg.DiscoveryTimeoutSeconds),
+	}
+	version, err := contentVersion(set)
+	if err != nil {
+		return nil, err
+	}
+	set.Version = version
+	return set, nil
+}
+
+func configPath() string {
+	exePath, _ := os.Executable()
+	return filepath.Join(filepath.Dir(exePath), "nexus_ops.json")
+}
+
+// resolvePath makes relative paths in the config relative to the executable, like the DB.
+func resolvePath(p string) string {
+	if p == "" || filepath.IsAbs(p) {
+		return p
+	}
+	exePath, _ := os.Executable()
+	return filepath.Join(filepath.Dir(exePath), p)
+}
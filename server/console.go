@@ -0,0 +1,312 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	pb "github.com/Mohammad-Mahdi82/NexusOps/pkg/monitor"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"google.golang.org/grpc/grpclog"
+)
+
+// rpcTimeout bounds every console call so a stalled daemon can't freeze a dialog.
+const rpcTimeout = 10 * time.Second
+
+// console is the operator TUI. It only talks to the daemon through the AdminService,
+// so it can run on another machine, detach with ESC and attach again at any time.
+type console struct {
+	addr  string
+	admin pb.AdminServiceClient
+	cfg   *pb.ConsoleConfig
+	loc   *Locale
+
+	mu        sync.Mutex
+	dash      *pb.Dashboard
+	connected bool
+	status    string // Last failed action, shown in the footer
+	focused   string // PC the operator tabbed to
+
+	app      *tview.Application
+	pages    *tview.Pages
+	mainFlex *tview.Flex
+	footer   *tview.TextView
+	pcTables []*tview.Table
+	root     *tview.Flex
+	logView  *tview.TextView // The daemon's log, toggled with L
+	showLogs bool
+}
+
+// logPanelHeight is the log panel's height in rows, border included.
+const logPanelHeight = 10
+
+// runConsole implements `nexus-server console`.
+func runConsole(args []string) error {
+	fs := flag.NewFlagSet("console", flag.ExitOnError)
+	addr, token := adminFlags(fs)
+	fs.Parse(args)
+
+	// Anything printed while tview owns the terminal garbles the screen
+	grpclog.SetLoggerV2(grpclog.NewLoggerV2(io.Discard, io.Discard, io.Discard))
+	log.SetOutput(io.Discard)
+
+	conn, err := dialAdmin(*addr, *token)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	c := &console{addr: *addr, admin: pb.NewAdminServiceClient(conn)}
+	ctx, cancel := c.rpcContext()
+	c.cfg, err = c.admin.GetConsoleConfig(ctx, &pb.ConsoleConfigRequest{})
+	cancel()
+	if err != nil {
+		return fmt.Errorf("can't reach the daemon at %s: %w", *addr, err)
+	}
+	c.loc = newLocale(LocaleConfig{Language: c.cfg.Language, Calendar: c.cfg.Calendar, PersianDigits: c.cfg.PersianDigits})
+
+	c.app = tview.NewApplication()
+	c.mainFlex = tview.NewFlex().SetDirection(tview.FlexColumn)
+	c.pages = tview.NewPages()
+	c.footer = tview.NewTextView().SetTextAlign(tview.AlignCenter).SetTextColor(tcell.ColorYellow)
+	c.app.SetInputCapture(c.handleKey)
+
+	c.logView = tview.NewTextView().SetDynamicColors(true).SetMaxLines(logBacklog)
+	c.logView.SetBorder(true).SetTitle(" " + c.loc.T("console.log") + " ")
+	c.showLogs = true
+
+	c.root = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(c.mainFlex, 0, 1, true).
+		AddItem(c.logView, logPanelHeight, 0, false).
+		AddItem(c.footer, 1, 1, false)
+	c.pages.AddPage("main", c.root, true, true)
+
+	go c.watch()
+	go c.watchLogs()
+
+	// ESC only detaches this console; the daemon keeps billing
+	return c.app.SetRoot(c.pages, true).Run()
+}
+
+// watch follows the daemon's events, reconnecting whenever the stream drops. Every
+// reconnect starts from a fresh snapshot, so nothing missed in between sticks around.
+func (c *console) watch() {
+	for {
+		stream, err := c.admin.WatchEvents(context.Background(), &pb.WatchEventsRequest{})
+		if err == nil {
+			for {
+				ev, err := stream.Recv()
+				if err != nil {
+					break
+				}
+				c.mu.Lock()
+				if ev.Type == pb.Event_SNAPSHOT {
+					c.dash, c.connected = ev.Dashboard, true
+				} else if c.dash != nil {
+					applyEvent(c.dash, ev)
+				}
+				c.mu.Unlock()
+				c.refreshUI()
+			}
+		}
+
+		c.mu.Lock()
+		c.connected = false
+		c.mu.Unlock()
+		c.refreshUI()
+		time.Sleep(2 * time.Second)
+	}
+}
+
+// applyEvent folds one event into the dashboard the console is showing.
+func applyEvent(d *pb.Dashboard, ev *pb.Event) {
+	switch ev.Type {
+	case pb.Event_CATALOG_UPDATED:
+		d.PendingGames = ev.PendingGames
+		return
+	case pb.Event_PAYMENT_RECORDED:
+		d.TodayRevenue = ev.TodayRevenue
+	}
+	if ev.Station == nil {
+		return
+	}
+
+	i := sort.Search(len(d.Stations), func(i int) bool { return d.Stations[i].Id >= ev.PcId })
+	found := i < len(d.Stations) && d.Stations[i].Id == ev.PcId
+	switch {
+	case !ev.Station.Connected:
+		if found {
+			d.Stations = append(d.Stations[:i], d.Stations[i+1:]...)
+		}
+	case found:
+		d.Stations[i] = ev.Station
+	default:
+		d.Stations = slices.Insert(d.Stations, i, ev.Station)
+	}
+}
+
+// watchLogs tails the daemon's log into the log panel, reconnecting like watch does.
+func (c *console) watchLogs() {
+	for {
+		stream, err := c.admin.WatchLogs(context.Background(), &pb.WatchLogsRequest{Backlog: 200})
+		if err == nil {
+			first := true
+			for {
+				e, err := stream.Recv()
+				if err != nil {
+					break
+				}
+				line := c.logLine(e)
+				reset := first
+				first = false
+				c.app.QueueUpdateDraw(func() {
+					if reset {
+						c.logView.Clear() // The backlog repeats what we had before reconnecting
+					}
+					fmt.Fprintln(c.logView, line)
+					c.logView.ScrollToEnd()
+				})
+			}
+		}
+		time.Sleep(2 * time.Second)
+	}
+}
+
+func (c *console) logLine(e *pb.LogEntry) string {
+	color := "white"
+	switch e.Level {
+	case "WARN":
+		color = "yellow"
+	case "ERROR":
+		color = "red"
+	case "DEBUG":
+		color = "gray"
+	}
+	ts := c.loc.Digits(time.Unix(e.Time, 0).Format("15:04:05"))
+	return fmt.Sprintf("[gray]%s[-] [%s]%-5s[-] %s", ts, color, e.Level, tview.Escape(e.Message))
+}
+
+// toggleLogs shows or hides the log panel.
+func (c *console) toggleLogs() {
+	c.showLogs = !c.showLogs
+	height := 0
+	if c.showLogs {
+		height = logPanelHeight
+	}
+	c.root.ResizeItem(c.logView, height, 0)
+}
+
+func (c *console) handleKey(event *tcell.EventKey) *tcell.EventKey {
+	front, _ := c.pages.GetFrontPage()
+	if event.Key() == tcell.KeyEscape {
+		// ESC closes an open dialog first, and only detaches from the main screen
+		if front != "main" {
+			c.pages.RemovePage(front)
+			c.refreshUI()
+			return nil
+		}
+		c.app.Stop()
+		return nil
+	}
+	if front != "main" {
+		return event
+	}
+
+	if event.Rune() == 'c' || event.Rune() == 'C' {
+		c.showClassifyDialog()
+		return nil
+	}
+	if event.Rune() == 'r' || event.Rune() == 'R' {
+		c.showReport(pb.ReportRequest_DAY)
+		return nil
+	}
+	if event.Rune() == 'l' || event.Rune() == 'L' {
+		c.toggleLogs()
+		return nil
+	}
+	if event.Key() == tcell.KeyTab {
+		for i, t := range c.pcTables {
+			if t.HasFocus() {
+				next := c.pcTables[(i+1)%len(c.pcTables)]
+				c.mu.Lock()
+				c.focused = next.GetTitle()
+				c.mu.Unlock()
+				c.app.SetFocus(next)
+				return nil
+			}
+		}
+	}
+
+	pcID := ""
+	for _, t := range c.pcTables {
+		if t.HasFocus() {
+			pcID = t.GetTitle()
+		}
+	}
+	if pcID == "" {
+		return event
+	}
+	switch {
+	case event.Rune() == 's' || event.Rune() == 'S':
+		c.toggleStation(pcID)
+	case event.Rune() == 't' || event.Rune() == 'T':
+		c.showTimedDialog(pcID)
+	case event.Rune() == 'k' || event.Rune() == 'K':
+		c.closeGames(pcID)
+	case event.Rune() == 'i' || event.Rune() == 'I':
+		c.showIdleDialog(pcID)
+	case event.Key() == tcell.KeyEnter:
+		c.showPaymentDialog(pcID)
+	default:
+		return event
+	}
+	return nil
+}
+
+// station finds a PC in the last dashboard.
+func (c *console) station(pcID string) *pb.Station {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.dash == nil {
+		return nil
+	}
+	for _, st := range c.dash.Stations {
+		if st.Id == pcID {
+			return st
+		}
+	}
+	return nil
+}
+
+func (c *console) rpcContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), rpcTimeout)
+}
+
+// do runs an admin call off the UI goroutine. The dashboard stream shows its effect;
+// a failure ends up in the footer.
+func (c *console) do(call func(ctx context.Context) error) {
+	go func() {
+		ctx, cancel := c.rpcContext()
+		defer cancel()
+		err := call(ctx)
+		c.app.QueueUpdateDraw(func() { c.setStatus(err) })
+		c.refreshUI()
+	}()
+}
+
+// setStatus records the outcome of the last action. Runs on the UI goroutine.
+func (c *console) setStatus(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.status = ""
+	if err != nil {
+		c.status = err.Error()
+	}
+}
@@ -1,37 +1,102 @@
 package main
 
 import (
+	"fmt"
 	"github.com/grandcat/zeroconf"
-	"log"
+	"log/slog"
 	"net"
+	"os"
+	"path"
+	"sync/atomic"
 )
 
 // Declare this at the package level so it's not garbage collected
 var beaconServer *zeroconf.Server
 
-func startDiscoveryBeacon() {
+// beaconActive is what the health service reports for the beacon.
+var beaconActive atomic.Bool
+
+func startDiscoveryBeacon(cfg DiscoveryConfig, netCfg NetworkConfig) {
 	var err error
 
-	// Explicitly grab all network interfaces to ensure we hit the Ethernet port
-	ifaces, err := net.Interfaces()
+	// Explicitly pick the interfaces so we hit the Ethernet port, but not Docker bridges
+	// zeroconf takes no interfaces to mean all of them, so without a match we don't register
+	ifaces, err := beaconInterfaces(netCfg)
 	if err != nil {
-		log.Println("Could not find interfaces:", err)
+		slog.Error("Discovery beacon not started", "err", err)
+		return
 	}
 
 	// Capture the server properly
 	beaconServer, err = zeroconf.Register(
-		"NexusOps-Server",
+		beaconInstance(cfg.Site),
 		"_nexusops._tcp",
 		"local.",
-		50051,
-		[]string{"version=1.0"}, // Good practice to have some TXT data
-		ifaces,                  // Tell it to use EVERY interface it finds
+		netCfg.BeaconPort(),
+		[]string{"version=1.0", "site=" + cfg.Site, "role=" + cfg.Role, fmt.Sprintf("priority=%d", cfg.Priority)},
+		ifaces,
 	)
 
 	if err != nil {
-		log.Println("Discovery Beacon Error:", err)
+		slog.Error("Discovery beacon failed", "err", err)
 		return
 	}
 
-	log.Println("mDNS Beacon active: NexusOps-Server is broadcasting on all ports.")
+	beaconActive.Store(true)
+
+	names := make([]string, 0, len(ifaces))
+	for _, iface := range ifaces {
+		names = append(names, iface.Name)
+	}
+	slog.Info("mDNS beacon active", "instance", beaconInstance(cfg.Site), "port", netCfg.BeaconPort(), "interfaces", names, "role", cfg.Role)
+}
+
+// beaconInstance names this server uniquely on the LAN. Two servers registering the same
+// instance name would fight over it, and clients would connect to whichever answered first.
+func beaconInstance(site string) string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "server"
+	}
+	if site == "" {
+		return "NexusOps-" + host
+	}
+	return fmt.Sprintf("NexusOps-%s-%s", site, host)
+}
+
+// beaconInterfaces returns the multicast-capable interfaces that are up and pass the
+// include/exclude lists. Finding none is an error.
+func beaconInterfaces(netCfg NetworkConfig) ([]net.Interface, error) {
+	all, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+
+	var ifaces []net.Interface
+	for _, iface := range all {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagMulticast == 0 {
+			continue
+		}
+		if len(netCfg.Interfaces) > 0 && !matchesAny(netCfg.Interfaces, iface.Name) {
+			continue
+		}
+		if matchesAny(netCfg.ExcludeInterfaces, iface.Name) {
+			continue
+		}
+		ifaces = append(ifaces, iface)
+	}
+	if len(ifaces) == 0 {
+		return nil, fmt.Errorf("no multicast interface is up and matches interfaces %q without matching exclude_interfaces %q",
+			netCfg.Interfaces, netCfg.ExcludeInterfaces)
+	}
+	return ifaces, nil
+}
+
+func matchesAny(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
 }
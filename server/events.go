@@ -0,0 +1,99 @@
+package main
+
+import (
+	"time"
+
+	pb "github.com/Mohammad-Mahdi82/NexusOps/pkg/monitor"
+)
+
+// eventBuffer is how many events a watcher may fall behind before it is cut off.
+const eventBuffer = 256
+
+// subscribe starts delivering events. Take it under s.mu together with the snapshot,
+// so no change falls between the two.
+func (s *server) subscribe() chan *pb.Event {
+	ch := make(chan *pb.Event, eventBuffer)
+	s.watchMu.Lock()
+	s.subscribers[ch] = true
+	s.watchMu.Unlock()
+	return ch
+}
+
+func (s *server) unsubscribe(ch chan *pb.Event) {
+	s.watchMu.Lock()
+	delete(s.subscribers, ch)
+	s.watchMu.Unlock()
+}
+
+func (s *server) watched() bool {
+	s.watchMu.Lock()
+	defer s.watchMu.Unlock()
+	return len(s.subscribers) > 0
+}
+
+// publish hands an event to every watcher. A watcher whose buffer is full gets its
+// channel closed instead of stalling billing; it resyncs by watching again.
+func (s *server) publish(ev *pb.Event) {
+	s.watchMu.Lock()
+	defer s.watchMu.Unlock()
+	for ch := range s.subscribers {
+		select {
+		case ch <- ev:
+		default:
+			delete(s.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// emit publishes an event about a PC along with the PC's state after the change.
+// Callers must hold s.mu.
+func (s *server) emit(typ pb.Event_Type, pcID string, fill func(ev *pb.Event)) {
+	if !s.watched() {
+		return // Building the station view costs a query
+	}
+	ev := &pb.Event{Type: typ, Time: time.Now().Unix(), PcId: pcID, Station: s.stationView(pcID)}
+	if fill != nil {
+		fill(ev)
+	}
+	s.publish(ev)
+}
+
+// announce is emit for callers that don't hold s.mu.
+func (s *server) announce(typ pb.Event_Type, pcID string, fill func(ev *pb.Event)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.emit(typ, pcID, fill)
+}
+
+// emitCatalog reports how many games wait for classification. Callers must hold s.mu.
+func (s *server) emitCatalog() {
+	if !s.watched() {
+		return
+	}
+	s.publish(&pb.Event{Type: pb.Event_CATALOG_UPDATED, Time: time.Now().Unix(), PendingGames: s.pendingCount()})
+}
+
+// emitGame reports a billed game starting, changing or stopping. Callers must hold s.mu.
+func (s *server) emitGame(pcID, oldGame, newGame string) {
+	was, is := playing(oldGame), playing(newGame)
+	typ := pb.Event_GAME_CHANGED
+	switch {
+	case !was && is:
+		typ = pb.Event_GAME_STARTED
+	case was && !is:
+		typ = pb.Event_GAME_STOPPED
+	case !was || oldGame == newGame:
+		return
+	}
+	s.emit(typ, pcID, func(ev *pb.Event) {
+		ev.Game = newGame
+		if was {
+			ev.PreviousGame = oldGame
+		}
+	})
+}
+
+func playing(game string) bool {
+	return game != "" && game != "Idle"
+}
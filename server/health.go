@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	pb "github.com/Mohammad-Mahdi82/NexusOps/pkg/monitor"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Health service names reported next to the overall "" status and NexusService.
+const (
+	healthDB     = "nexusops.db"
+	healthBeacon = "nexusops.beacon"
+)
+
+const healthCheckInterval = 10 * time.Second
+
+// runHealthChecks keeps the standard gRPC health service current, so monitoring can
+// probe the Pi with grpc_health_probe or grpcurl.
+func (s *server) runHealthChecks(hs *health.Server) {
+	for {
+		s.checkHealth(hs)
+		time.Sleep(healthCheckInterval)
+	}
+}
+
+func (s *server) checkHealth(hs *health.Server) {
+	db := healthpb.HealthCheckResponse_SERVING
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	// A ping never reaches SQLite's file, so read a row to find a missing or locked database
+	if err := s.db.WithContext(ctx).Exec("SELECT 1 FROM sessions LIMIT 1").Error; err != nil {
+		db = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+
+	beacon := healthpb.HealthCheckResponse_SERVING
+	if !beaconActive.Load() {
+		beacon = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+
+	hs.SetServingStatus(healthDB, db)
+	hs.SetServingStatus(healthBeacon, beacon)
+	// Sentries can still reach us by address without the beacon, so only the DB
+	// decides whether billing works
+	hs.SetServingStatus("", db)
+	hs.SetServingStatus(pb.NexusService_ServiceDesc.ServiceName, db)
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestCheckHealthReadsTheDatabase(t *testing.T) {
+	s := newTestServer(t)
+	hs := health.NewServer()
+	dbStatus := func() healthpb.HealthCheckResponse_ServingStatus {
+		resp, err := hs.Check(context.Background(), &healthpb.HealthCheckRequest{Service: healthDB})
+		if err != nil {
+			t.Fatalf("Check: %v", err)
+		}
+		return resp.Status
+	}
+
+	s.checkHealth(hs)
+	if got := dbStatus(); got != healthpb.HealthCheckResponse_SERVING {
+		t.Fatalf("healthy database reported %v", got)
+	}
+
+	// A pool can still hand out connections to a database whose tables are gone
+	if err := s.db.Exec("DROP TABLE sessions").Error; err != nil {
+		t.Fatal(err)
+	}
+	s.checkHealth(hs)
+	if got := dbStatus(); got != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("unreadable database reported %v", got)
+	}
+}
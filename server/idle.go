@@ -0,0 +1,114 @@
+package main
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/Mohammad-Mahdi82/NexusOps/server/models"
+)
+
+// Idle stages escalate as a PC goes without keyboard or mouse input.
+const (
+	idleActive = iota
+	idleWarned // Customer got a notice
+	idlePaused // Billing stopped accruing
+	idleLocked // Session ended and the game closed
+)
+
+// loadStations reads every known PC into memory.
+func (s *server) loadStations() {
+	var stations []models.Station
+	s.db.Find(&stations)
+	for i := range stations {
+		s.stations[stations[i].ID] = &stations[i]
+	}
+}
+
+// touchStation registers a PC the first time it connects and records when it was last seen.
+// Callers must hold s.mu.
+func (s *server) touchStation(pcID string) {
+	st := s.stationFor(pcID)
+	st.LastSeen = time.Now()
+	if err := s.db.Save(st).Error; err != nil {
+		slog.Error("Could not save station", "pc", pcID, "err", err)
+	}
+}
+
+// idlePolicy is the server-wide policy with the station's overrides applied. Callers must hold s.mu.
+func (s *server) idlePolicy(pcID string) IdleConfig {
+	p := s.cfg.Idle
+	if st, ok := s.stations[pcID]; ok {
+		if st.IdleWarnMinutes != nil {
+			p.WarnMinutes = *st.IdleWarnMinutes
+		}
+		if st.IdlePauseMinutes != nil {
+			p.PauseMinutes = *st.IdlePauseMinutes
+		}
+		if st.IdleLockMinutes != nil {
+			p.LockMinutes = *st.IdleLockMinutes
+		}
+	}
+	return p
+}
+
+// setIdlePolicy stores a station's overrides; nil values go back to the server default.
+func (s *server) setIdlePolicy(pcID string, warn, pause, lock *int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st := s.stationFor(pcID)
+	st.IdleWarnMinutes, st.IdlePauseMinutes, st.IdleLockMinutes = warn, pause, lock
+	return s.db.Save(st).Error
+}
+
+// applyIdlePolicy moves a PC through the idle stages and returns the message the
+// customer should see, if the stage just changed. Callers must hold s.mu.
+func (s *server) applyIdlePolicy(pcID string, idle time.Duration) string {
+	p := s.idlePolicy(pcID)
+	reached := func(minutes int) bool {
+		return minutes > 0 && idle >= time.Duration(minutes)*time.Minute
+	}
+
+	stage := idleActive
+	switch {
+	case reached(p.LockMinutes):
+		stage = idleLocked
+	case reached(p.PauseMinutes):
+		stage = idlePaused
+	case reached(p.WarnMinutes):
+		stage = idleWarned
+	}
+
+	prev := s.idleStage[pcID]
+	// A PC sitting at the desktop isn't billed, so there is nothing to warn about or pause
+	if game := s.pcStates[pcID]; stage > prev && (game == "" || game == "Idle") {
+		return ""
+	}
+	s.idleStage[pcID] = stage
+	if stage == prev {
+		return ""
+	}
+
+	minutes := int(idle.Minutes())
+	switch stage {
+	case idleWarned:
+		s.pcNotices[pcID] = s.loc.T("idle.warned", minutes)
+		return s.loc.T("notice.idle_warn")
+	case idlePaused:
+		s.pcNotices[pcID] = s.loc.T("idle.paused", minutes)
+		return s.loc.T("notice.idle_paused")
+	case idleLocked:
+		s.pcNotices[pcID] = s.loc.T("idle.locked", minutes)
+		s.killSignals[pcID] = true
+		if err := s.closeStation(pcID); err != nil {
+			slog.Error("Could not lock station", "pc", pcID, "err", err)
+		}
+		return s.loc.T("notice.idle_locked")
+	}
+
+	// Someone is back at the keyboard
+	if prev != idleActive {
+		delete(s.pcNotices, pcID)
+	}
+	return ""
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestApplyIdlePolicy(t *testing.T) {
+	s := newTestServer(t)
+	s.cfg.Idle = IdleConfig{WarnMinutes: 5, PauseMinutes: 10, LockMinutes: 20}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pcStates["PC-01"] = "cs2.exe"
+
+	steps := []struct {
+		name   string
+		idle   time.Duration
+		notice string
+		stage  int
+	}{
+		{name: "active", idle: time.Minute, stage: idleActive},
+		{name: "warn", idle: 5 * time.Minute, notice: "notice.idle_warn", stage: idleWarned},
+		{name: "still warned", idle: 7 * time.Minute, stage: idleWarned},
+		{name: "pause", idle: 10 * time.Minute, notice: "notice.idle_paused", stage: idlePaused},
+		{name: "lock", idle: 20 * time.Minute, notice: "notice.idle_locked", stage: idleLocked},
+		{name: "still locked", idle: 25 * time.Minute, stage: idleLocked},
+		{name: "back at the keyboard", idle: 0, stage: idleActive},
+	}
+	for _, step := range steps {
+		got := s.applyIdlePolicy("PC-01", step.idle)
+		want := ""
+		if step.notice != "" {
+			want = s.loc.T(step.notice)
+		}
+		if got != want || s.idleStage["PC-01"] != step.stage {
+			t.Errorf("%s: notice %q at stage %d, want %q at stage %d", step.name, got, s.idleStage["PC-01"], want, step.stage)
+		}
+		if step.stage == idleLocked && !s.killSignals["PC-01"] {
+			t.Errorf("%s: games weren't closed", step.name)
+		}
+	}
+	if _, ok := s.pcNotices["PC-01"]; ok {
+		t.Errorf("dashboard note %q left after input resumed", s.pcNotices["PC-01"])
+	}
+}
+
+func TestApplyIdlePolicyWithoutGame(t *testing.T) {
+	s := newTestServer(t)
+	s.cfg.Idle = IdleConfig{WarnMinutes: 5, PauseMinutes: 10, LockMinutes: 20}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, game := range []string{"", "Idle"} {
+		s.pcStates["PC-01"] = game
+		for _, idle := range []time.Duration{5 * time.Minute, 10 * time.Minute, time.Hour} {
+			if notice := s.applyIdlePolicy("PC-01", idle); notice != "" || s.idleStage["PC-01"] != idleActive {
+				t.Errorf("%q idle %v: notice %q at stage %d, want none", game, idle, notice, s.idleStage["PC-01"])
+			}
+		}
+	}
+	if s.killSignals["PC-01"] || s.pcNotices["PC-01"] != "" {
+		t.Error("a PC without a game was locked or noted")
+	}
+}
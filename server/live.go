@@ -0,0 +1,86 @@
+package main
+
+import (
+	"log/slog"
+	"time"
+
+	pb "github.com/Mohammad-Mahdi82/NexusOps/pkg/monitor"
+	"github.com/Mohammad-Mahdi82/NexusOps/server/models"
+	"gorm.io/gorm"
+)
+
+// repriceInterval is how often running sessions are re-priced between heartbeats.
+const repriceInterval = 5 * time.Second
+
+// liveSession is the in-memory copy of a PC's running session. Heartbeats and the
+// housekeeping loop only touch this copy; flushSessions writes it out in batches so a
+// full cafe doesn't turn every heartbeat into an SD card write.
+type liveSession struct {
+	sess  models.Session
+	dirty bool
+}
+
+// flushSessions writes every changed live session in one transaction. Callers must hold s.mu.
+func (s *server) flushSessions() {
+	var pending []*liveSession
+	for _, ls := range s.liveSessions {
+		if ls.dirty {
+			pending = append(pending, ls)
+		}
+	}
+	if len(pending) == 0 {
+		return
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		for _, ls := range pending {
+			if err := saveLiveSession(tx, &ls.sess); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		slog.Error("Could not save live sessions", "err", err)
+		return
+	}
+	for _, ls := range pending {
+		ls.dirty = false
+	}
+}
+
+// saveLiveSession writes the columns a running session changes.
+func saveLiveSession(db *gorm.DB, sess *models.Session) error {
+	return db.Model(&models.Session{}).Where("id = ?", sess.ID).Updates(map[string]interface{}{
+		"end_time": sess.EndTime, "duration_minutes": sess.DurationMinutes, "fee": sess.Fee,
+		"paused_seconds": sess.PausedSeconds, "is_active": sess.IsActive,
+	}).Error
+}
+
+// runHousekeeping keeps fees moving between heartbeats, which now only arrive when
+// something changes, and flushes live sessions every db_flush_seconds.
+func (s *server) runHousekeeping() {
+	flushEvery := time.Duration(s.cfg.DBFlush) * time.Second
+	lastFlush := time.Now()
+
+	for range time.Tick(repriceInterval) {
+		s.mu.Lock()
+		for pcID, ls := range s.liveSessions {
+			minutes := ls.sess.DurationMinutes
+			s.updateLiveSession(pcID)
+			if ls.sess.DurationMinutes != minutes {
+				s.emit(pb.Event_SESSION_UPDATED, pcID, func(ev *pb.Event) { ev.Session = toPBSession(ls.sess) })
+			}
+		}
+		if time.Since(lastFlush) >= flushEvery {
+			s.flushSessions()
+			lastFlush = time.Now()
+		}
+		expired := s.expiredStations()
+		s.mu.Unlock()
+
+		for _, pcID := range expired {
+			s.sendCommand(pcID)
+		}
+	}
+}
@@ -0,0 +1,305 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// messages holds every operator-facing string. English is the fallback for missing keys.
+var messages = map[string]map[string]string{
+	"en": {
+		"footer":           " [TAB] Switch PC | [ENTER] Pay | [S] Open/Lock | [T] Timed | [K] Close games | [I] Idle policy | [C] Classify | [R] Report | [L] Log | [ESC] Detach ",
+		"footer.pending":   " | %d new game(s) to classify",
+		"footer.today":     "| Today: %s ",
+		"no_pcs":           "No PCs Connected.",
+		"col.game":         "GAME",
+		"col.min":          "MIN",
+		"col.fee":          "FEE",
+		"total":            "TOTAL",
+		"dialog.settle":    "Settle %s?",
+		"dialog.cancel":    "Cancel",
+		"dialog.discount":  "Discount",
+		"classify.title":   " Classify %s ",
+		"classify.name":    "Title",
+		"classify.genre":   "Genre",
+		"classify.rate":    "Rate class",
+		"classify.save":    "Save",
+		"classify.skip":    "Skip",
+		"classify.ignore":  "Not a game",
+		"classify.none":    "No games waiting for classification.",
+		"method.cash":      "Cash",
+		"method.card":      "Card",
+		"receipt.number":   "Receipt",
+		"receipt.pc":       "PC",
+		"receipt.date":     "Date",
+		"receipt.minutes":  "%d min",
+		"receipt.subtotal": "Subtotal",
+		"receipt.discount": "Discount",
+		"receipt.tax":      "Tax %s%%",
+		"receipt.total":    "TOTAL",
+		"receipt.paid_by":  "Paid by",
+		"receipt.operator": "Operator",
+
+		"close.closed":      "%s closed",
+		"close.killed":      "%s force-closed",
+		"close.failed":      "Could not close %s",
+		"close.not_running": "%s was not running",
+
+		"idle.warned":        "Idle %d min",
+		"idle.paused":        "Idle %d min, billing paused",
+		"idle.locked":        "Idle %d min, session ended",
+		"idle.title":         " Idle policy for %s ",
+		"idle.warn":          "Warn after (min)",
+		"idle.pause":         "Pause billing after (min)",
+		"idle.lock":          "End session after (min)",
+		"idle.default":       "default %d, 0 = off",
+		"notice.idle_warn":   "Are you still there? Billing will pause soon if the PC stays idle.",
+		"notice.idle_paused": "Billing is paused until you use the PC again.",
+		"notice.idle_locked": "This PC was idle for too long. Your session has ended.",
+		"dialog.save":        "Save",
+		"lock.message":       "This PC is locked. Please see the front desk to start a session.",
+		"lock.expired":       "Time is up, PC locked",
+		"lock.locked":        "locked",
+		"lock.open":          "open",
+		"lock.until":         "until %s",
+		"lock.timed":         "Unlock %s for how long?",
+		"lock.minutes":       "%d min",
+
+		"console.disconnected": "Not connected to the NexusOps daemon at %s, retrying...",
+		"console.log":          "Server log",
+		"report.title":         " Report %s – %s ",
+		"report.summary":       "Revenue %s | Payments %d | Sessions %d | Billed %d min | Average session %d min",
+		"report.period":        "By period",
+		"report.pc":            "By PC",
+		"report.game":          "By game",
+		"report.operator":      "By operator",
+		"report.heatmap":       "PC use by hour, %",
+		"report.hint":          " [D] %d days by day | [W] %d weeks | [M] %d months | [TAB] Next table | [ESC] Back ",
+		"col.revenue":          "REVENUE",
+		"weekday.0":            "Sun",
+		"weekday.1":            "Mon",
+		"weekday.2":            "Tue",
+		"weekday.3":            "Wed",
+		"weekday.4":            "Thu",
+		"weekday.5":            "Fri",
+		"weekday.6":            "Sat",
+	},
+	"fa": {
+		"footer":           " [TAB] سیستم بعدی | [ENTER] پرداخت | [S] باز/قفل | [T] زمان‌دار | [K] بستن بازی | [I] بیکاری | [C] دسته‌بندی | [R] آمار | [L] گزارش | [ESC] جدا شدن ",
+		"footer.pending":   " | %d بازی جدید در انتظار دسته‌بندی",
+		"footer.today":     "| امروز: %s ",
+		"no_pcs":           "هیچ سیستمی متصل نیست.",
+		"col.game":         "بازی",
+		"col.min":          "دقیقه",
+		"col.fee":          "هزینه",
+		"total":            "جمع کل",
+		"dialog.settle":    "تسویه حساب %s؟",
+		"dialog.cancel":    "انصراف",
+		"dialog.discount":  "تخفیف",
+		"classify.title":   " دسته‌بندی %s ",
+		"classify.name":    "عنوان",
+		"classify.genre":   "ژانر",
+		"classify.rate":    "کلاس نرخ",
+		"classify.save":    "ذخیره",
+		"classify.skip":    "بعدی",
+		"classify.ignore":  "بازی نیست",
+		"classify.none":    "بازی جدیدی برای دسته‌بندی وجود ندارد.",
+		"method.cash":      "نقدی",
+		"method.card":      "کارت",
+		"receipt.number":   "رسید",
+		"receipt.pc":       "سیستم",
+		"receipt.date":     "تاریخ",
+		"receipt.minutes":  "%d دقیقه",
+		"receipt.subtotal": "جمع",
+		"receipt.discount": "تخفیف",
+		"receipt.tax":      "مالیات %s%%",
+		"receipt.total":    "مبلغ کل",
+		"receipt.paid_by":  "روش پرداخت",
+		"receipt.operator": "اپراتور",
+
+		"close.closed":      "%s بسته شد",
+		"close.killed":      "%s به اجبار بسته شد",
+		"close.failed":      "بستن %s ممکن نشد",
+		"close.not_running": "%s در حال اجرا نبود",
+
+		"idle.warned":        "بیکار %d دقیقه",
+		"idle.paused":        "بیکار %d دقیقه، محاسبه هزینه متوقف شد",
+		"idle.locked":        "بیکار %d دقیقه، جلسه بسته شد",
+		"idle.title":         " سیاست بیکاری %s ",
+		"idle.warn":          "هشدار پس از (دقیقه)",
+		"idle.pause":         "توقف محاسبه پس از (دقیقه)",
+		"idle.lock":          "پایان جلسه پس از (دقیقه)",
+		"idle.default":       "پیش‌فرض %d، صفر = خاموش",
+		"notice.idle_warn":   "هنوز اینجا هستید؟ اگر سیستم بیکار بماند محاسبه هزینه متوقف می‌شود.",
+		"notice.idle_paused": "محاسبه هزینه تا استفاده دوباره از سیستم متوقف شد.",
+		"notice.idle_locked": "این سیستم مدت زیادی بیکار بود. جلسه شما به پایان رسید.",
+		"dialog.save":        "ذخیره",
+		"lock.message":       "این سیستم قفل است. برای شروع جلسه به پیشخوان مراجعه کنید.",
+		"lock.expired":       "زمان تمام شد، سیستم قفل شد",
+		"lock.locked":        "قفل",
+		"lock.open":          "باز",
+		"lock.until":         "تا %s",
+		"lock.timed":         "%s برای چه مدت باز شود؟",
+		"lock.minutes":       "%d دقیقه",
+
+		"console.disconnected": "اتصال به سرویس NexusOps در %s برقرار نیست، تلاش دوباره...",
+		"console.log":          "گزارش سرور",
+		"report.title":         " آمار %s تا %s ",
+		"report.summary":       "درآمد %s | پرداخت‌ها %d | جلسه‌ها %d | زمان محاسبه‌شده %d دقیقه | میانگین جلسه %d دقیقه",
+		"report.period":        "بر اساس دوره",
+		"report.pc":            "بر اساس سیستم",
+		"report.game":          "بر اساس بازی",
+		"report.operator":      "بر اساس اپراتور",
+		"report.heatmap":       "درصد استفاده از سیستم‌ها در هر ساعت",
+		"report.hint":          " [D] %d روز اخیر | [W] %d هفته | [M] %d ماه | [TAB] جدول بعدی | [ESC] بازگشت ",
+		"col.revenue":          "درآمد",
+		"weekday.0":            "یکشنبه",
+		"weekday.1":            "دوشنبه",
+		"weekday.2":            "سه‌شنبه",
+		"weekday.3":            "چهارشنبه",
+		"weekday.4":            "پنجشنبه",
+		"weekday.5":            "جمعه",
+		"weekday.6":            "شنبه",
+	},
+}
+
+var rtlLanguages = map[string]bool{"fa": true, "ar": true, "he": true}
+
+// Locale formats strings, dates and numbers the way the staff configured.
+type Locale struct {
+	lang          string
+	jalali        bool
+	persianDigits bool
+}
+
+func newLocale(cfg LocaleConfig) *Locale {
+	lang := cfg.Language
+	if _, ok := messages[lang]; !ok {
+		lang = "en"
+	}
+	return &Locale{
+		lang:          lang,
+		jalali:        cfg.Calendar == "jalali",
+		persianDigits: cfg.PersianDigits,
+	}
+}
+
+// T looks up a message and fills in its arguments. Only numeric arguments get Persian
+// digits: PC names, paths and the like are shown as they are, and callers pass amounts
+// they formatted themselves through Digits.
+func (l *Locale) T(key string, args ...interface{}) string {
+	msg, ok := messages[l.lang][key]
+	if !ok {
+		if msg, ok = messages["en"][key]; !ok {
+			msg = key
+		}
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	if l.persianDigits {
+		args = append([]interface{}(nil), args...)
+		for i, arg := range args {
+			if isNumber(arg) {
+				args[i] = localNumber{arg, l}
+			}
+		}
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+func isNumber(v interface{}) bool {
+	if _, ok := v.(decimal.Decimal); ok {
+		return true
+	}
+	switch reflect.ValueOf(v).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// localNumber formats a number with the verb the message gives it, then swaps its digits.
+type localNumber struct {
+	v   interface{}
+	loc *Locale
+}
+
+func (n localNumber) Format(f fmt.State, verb rune) {
+	fmt.Fprint(f, n.loc.Digits(fmt.Sprintf(fmt.FormatString(f, verb), n.v)))
+}
+
+// Method translates a payment method key, leaving custom methods as configured.
+func (l *Locale) Method(method string) string {
+	key := "method." + strings.ToLower(method)
+	if _, ok := messages["en"][key]; !ok {
+		return method
+	}
+	return l.T(key)
+}
+
+func (l *Locale) RTL() bool {
+	return rtlLanguages[l.lang]
+}
+
+// Digits swaps ASCII digits for Persian ones when enabled.
+func (l *Locale) Digits(s string) string {
+	if !l.persianDigits {
+		return s
+	}
+	return persianDigitReplacer.Replace(s)
+}
+
+var persianDigitReplacer = strings.NewReplacer(
+	"0", "۰", "1", "۱", "2", "۲", "3", "۳", "4", "۴",
+	"5", "۵", "6", "۶", "7", "۷", "8", "۸", "9", "۹",
+)
+
+// Date formats a day as YYYY-MM-DD (or YYYY/MM/DD in the Jalali calendar).
+func (l *Locale) Date(t time.Time) string {
+	if l.jalali {
+		jy, jm, jd := gregorianToJalali(t.Year(), int(t.Month()), t.Day())
+		return l.Digits(fmt.Sprintf("%04d/%02d/%02d", jy, jm, jd))
+	}
+	return l.Digits(t.Format("2006-01-02"))
+}
+
+func (l *Locale) DateTime(t time.Time) string {
+	return l.Date(t) + " " + l.Digits(t.Format("15:04"))
+}
+
+// gregorianToJalali converts a civil date to the Solar Hijri calendar
+// using the 33-year cycle arithmetic that Iranian software commonly ships.
+func gregorianToJalali(gy, gm, gd int) (jy, jm, jd int) {
+	monthDays := [12]int{0, 31, 59, 90, 120, 151, 181, 212, 243, 273, 304, 334}
+
+	gy2 := gy
+	if gm > 2 {
+		gy2 = gy + 1
+	}
+	days := 355666 + 365*gy + (gy2+3)/4 - (gy2+99)/100 + (gy2+399)/400 + gd + monthDays[gm-1]
+
+	jy = -1595 + 33*(days/12053)
+	days %= 12053
+	jy += 4 * (days / 1461)
+	days %= 1461
+	if days > 365 {
+		jy += (days - 1) / 365
+		days = (days - 1) % 365
+	}
+
+	if days < 186 {
+		jm = 1 + days/31
+		jd = 1 + days%31
+	} else {
+		jm = 7 + (days-186)/30
+		jd = 1 + (days-186)%30
+	}
+	return
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestLocaleDigits(t *testing.T) {
+	fa := newLocale(LocaleConfig{Language: "fa", PersianDigits: true})
+	en := newLocale(LocaleConfig{Language: "en"})
+
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{name: "PC names stay as configured", got: fa.T("dialog.settle", "PC-01"), want: "تسویه حساب PC-01؟"},
+		{name: "numbers are localized", got: fa.T("receipt.minutes", 45), want: "۴۵ دقیقه"},
+		{name: "the verb still applies", got: fa.T("report.summary", "x", int32(3), 12, 90, 30),
+			want: "درآمد x | پرداخت‌ها ۳ | جلسه‌ها ۱۲ | زمان محاسبه‌شده ۹۰ دقیقه | میانگین جلسه ۳۰ دقیقه"},
+		{name: "decimals are numbers", got: fa.T("receipt.tax", decimal.NewFromInt(9)), want: "مالیات ۹%"},
+		{name: "off by default", got: en.T("receipt.minutes", 45), want: "45 min"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+
+	pb "github.com/Mohammad-Mahdi82/NexusOps/pkg/monitor"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// LogConfig sends the daemon's log to a rotating file as well as stderr.
+type LogConfig struct {
+	Path       string `json:"path"`  // Relative to the executable, empty for stderr only
+	Level      string `json:"level"` // "debug", "info", "warn" or "error"
+	MaxSizeMB  int    `json:"max_size_mb"`
+	MaxBackups int    `json:"max_backups"`
+	MaxAgeDays int    `json:"max_age_days"`
+}
+
+// logBacklog is how many recent lines an attaching console gets.
+const logBacklog = 500
+
+// setupLogging makes slog, and the stdlib log that libraries still use, write to
+// stderr, the log file and the hub consoles watch.
+func setupLogging(cfg LogConfig, hub *logHub) error {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(cfg.Level)); err != nil {
+		return fmt.Errorf("log.level: %w", err)
+	}
+
+	var w io.Writer = os.Stderr
+	if cfg.Path != "" {
+		w = io.MultiWriter(os.Stderr, &lumberjack.Logger{
+			Filename:   resolvePath(cfg.Path),
+			MaxSize:    cfg.MaxSizeMB,
+			MaxBackups: cfg.MaxBackups,
+			MaxAge:     cfg.MaxAgeDays,
+		})
+	}
+	h := slog.NewTextHandler(w, &slog.HandlerOptions{Level: level})
+	slog.SetDefault(slog.New(&hubHandler{Handler: h, hub: hub}))
+	return nil
+}
+
+// logHub keeps the latest log lines and hands new ones to watching consoles.
+type logHub struct {
+	mu          sync.Mutex
+	recent      []*pb.LogEntry
+	subscribers map[chan *pb.LogEntry]bool
+}
+
+func newLogHub() *logHub {
+	return &logHub{subscribers: make(map[chan *pb.LogEntry]bool)}
+}
+
+func (h *logHub) add(e *pb.LogEntry) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.recent = append(h.recent, e)
+	if len(h.recent) > logBacklog {
+		h.recent = h.recent[len(h.recent)-logBacklog:]
+	}
+	for ch := range h.subscribers {
+		select {
+		case ch <- e:
+		default: // A console that can't keep up misses lines rather than stalling the daemon
+		}
+	}
+}
+
+// subscribe returns up to backlog recent lines and a channel for the ones after them.
+func (h *logHub) subscribe(backlog int) ([]*pb.LogEntry, chan *pb.LogEntry) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	ch := make(chan *pb.LogEntry, 64)
+	h.subscribers[ch] = true
+	start := max(len(h.recent)-backlog, 0)
+	return append([]*pb.LogEntry(nil), h.recent[start:]...), ch
+}
+
+func (h *logHub) unsubscribe(ch chan *pb.LogEntry) {
+	h.mu.Lock()
+	delete(h.subscribers, ch)
+	h.mu.Unlock()
+}
+
+// hubHandler copies every record that passes the level filter into the hub. Keys are
+// qualified by their groups with dots, the way slog's text handler writes them.
+type hubHandler struct {
+	slog.Handler
+	hub    *logHub
+	attrs  string // Attributes from WithAttrs, already written out
+	prefix string // Groups opened by WithGroup, e.g. "db."
+}
+
+func (h *hubHandler) Handle(ctx context.Context, r slog.Record) error {
+	var b strings.Builder
+	b.WriteString(r.Message)
+	b.WriteString(h.attrs)
+	r.Attrs(func(a slog.Attr) bool {
+		writeAttr(&b, h.prefix, a)
+		return true
+	})
+	h.hub.add(&pb.LogEntry{Time: r.Time.Unix(), Level: r.Level.String(), Message: b.String()})
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *hubHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var b strings.Builder
+	for _, a := range attrs {
+		writeAttr(&b, h.prefix, a)
+	}
+	return &hubHandler{Handler: h.Handler.WithAttrs(attrs), hub: h.hub, attrs: h.attrs + b.String(), prefix: h.prefix}
+}
+
+func (h *hubHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &hubHandler{Handler: h.Handler.WithGroup(name), hub: h.hub, attrs: h.attrs, prefix: h.prefix + name + "."}
+}
+
+// writeAttr appends " key=value", flattening group values into dotted keys.
+func writeAttr(b *strings.Builder, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, g := range a.Value.Group() {
+			writeAttr(b, prefix, g)
+		}
+		return
+	}
+	fmt.Fprintf(b, " %s%s=%v", prefix, a.Key, a.Value)
+}
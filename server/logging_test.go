@@ -0,0 +1,30 @@
+package main
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+)
+
+func TestHubHandlerGroups(t *testing.T) {
+	hub := newLogHub()
+	logger := slog.New(&hubHandler{Handler: slog.NewTextHandler(io.Discard, nil), hub: hub})
+
+	logger.With("pc", "PC-01").WithGroup("db").With("table", "sessions").
+		WithGroup("query").Info("Slow", "ms", 120, slog.Group("row", "id", "s1"))
+	logger.WithGroup("empty").Info("No attrs")
+
+	want := []string{
+		"Slow pc=PC-01 db.table=sessions db.query.ms=120 db.query.row.id=s1",
+		"No attrs",
+	}
+	got, _ := hub.subscribe(len(want))
+	if len(got) != len(want) {
+		t.Fatalf("%d lines in the hub, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i].Message != want[i] {
+			t.Errorf("line %d = %q, want %q", i, got[i].Message, want[i])
+		}
+	}
+}
@@ -1,133 +1,186 @@
 package main
 
 import (
+	"fmt"
 	"log"
+	"log/slog"
 	"net"
+	"net/http"
+	"os"
 	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"syscall"
 	"time"
 
 	pb "github.com/Mohammad-Mahdi82/NexusOps/pkg/monitor"
-	"github.com/gdamore/tcell/v2"
-	"github.com/rivo/tview"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 )
 
-func openWindowsFirewall() {
+func openWindowsFirewall(port int) {
 	// Delete old rules first so we don't have duplicates
 	exec.Command("netsh", "advfirewall", "firewall", "delete", "rule", "name=NexusOps_Data").Run()
 	exec.Command("netsh", "advfirewall", "firewall", "delete", "rule", "name=NexusOps_Discovery").Run()
 
 	// Now add them fresh
 	exec.Command("netsh", "advfirewall", "firewall", "add", "rule",
-		"name=NexusOps_Data", "dir=in", "action=allow", "protocol=TCP", "localport=50051", "profile=any").Run()
+		"name=NexusOps_Data", "dir=in", "action=allow", "protocol=TCP", fmt.Sprintf("localport=%d", port), "profile=any").Run()
 
 	exec.Command("netsh", "advfirewall", "firewall", "add", "rule",
 		"name=NexusOps_Discovery", "dir=in", "action=allow", "protocol=UDP", "localport=5353", "profile=any").Run()
 }
 
 func main() {
+	if len(os.Args) > 1 {
+		subcommands := map[string]func([]string) error{
+			"console": runConsole,
+			"report":  runReport,
+			"export":  runExport,
+			"import":  runImport,
+		}
+		if run, ok := subcommands[os.Args[1]]; ok {
+			if err := run(os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return
+		}
+	}
+	runDaemon()
+}
 
-	openWindowsFirewall()
-
-	// 1. Initialize Database
-	db, err := InitDB()
+// runDaemon serves Sentries and consoles until it is told to stop. It needs no terminal,
+// so it can run as a service and consoles come and go without touching billing.
+func runDaemon() {
+	// 1. Load Config & Initialize Database
+	cfg, err := LoadConfig()
 	if err != nil {
-		log.Fatal(err)
+		log.Fatal("Invalid config: ", err)
+	}
+	logs := newLogHub()
+	if err := setupLogging(cfg.Log, logs); err != nil {
+		log.Fatal("Invalid config: ", err)
+	}
+	// fatal replaces log.Fatal once slog owns the output
+	fatal := func(msg string, err error) {
+		slog.Error(msg, "err", err)
+		os.Exit(1)
 	}
 
-	// 2. Setup TUI Application
-	app := tview.NewApplication()
-	mainFlex := tview.NewFlex().SetDirection(tview.FlexColumn)
-
-	nexusSrv := &server{
-		db:               db,
-		pcStates:         make(map[string]string),
-		activeSessionIDs: make(map[string]string),
-		killSignals:      make(map[string]bool),
-		app:              app,
-		mainFlex:         mainFlex,
+	listenPort, _ := cfg.Network.ListenPort()
+	openWindowsFirewall(listenPort)
+
+	if _, err := buildProcessRules(cfg.ProcessRules, nil); err != nil {
+		fatal("Invalid config", err)
+	}
+	settings, err := buildClientSettings(cfg.Client)
+	if err != nil {
+		fatal("Invalid config", err)
 	}
-	nexusSrv.startupCleanup()
 
-	// 3. TUI Input Capture (Hotkeys)
-	app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-		if event.Key() == tcell.KeyEscape {
-			app.Stop()
-		}
-		if event.Key() == tcell.KeyTab {
-			for i, t := range nexusSrv.pcTables {
-				if t.HasFocus() {
-					next := (i + 1) % len(nexusSrv.pcTables)
-					app.SetFocus(nexusSrv.pcTables[next])
-					return nil
-				}
-			}
-		}
-		if event.Key() == tcell.KeyEnter {
-			for _, t := range nexusSrv.pcTables {
-				if t.HasFocus() {
-					pcID := t.GetTitle()
-					if pcID != "" {
-						go func(id string) {
-							nexusSrv.MarkAsPaid(id)
-							nexusSrv.refreshUI()
-						}(pcID)
-					}
-					return nil
-				}
-			}
-		}
-		return event
-	})
+	db, err := InitDB(resolvePath(cfg.DBPath))
+	if err != nil {
+		fatal("Database unavailable", err)
+	}
 
-	// 4. UI Layout
-	footer := tview.NewTextView().SetText(" [TAB] Switch PC | [ENTER] Pay | [ESC] Exit ").
-		SetTextAlign(tview.AlignCenter).SetTextColor(tcell.ColorYellow)
+	printer, err := newPrinter(cfg.Printer, cfg.Locale.Language)
+	if err != nil {
+		slog.Warn("Receipt printer disabled", "err", err)
+	}
 
-	root := tview.NewFlex().SetDirection(tview.FlexRow).
-		AddItem(mainFlex, 0, 1, true).
-		AddItem(footer, 1, 1, false)
+	// 2. Server state
+	nexusSrv := newServer(cfg, db, printer, settings, logs)
+	nexusSrv.startupCleanup()
+	nexusSrv.loadCatalog()
+	nexusSrv.loadStations()
+	nexusSrv.rebuildProcessRules()
 
-	// 5. Networking & Discovery
+	// 3. Networking & Discovery
 	// Start the mDNS Beacon so Clients can find the Pi
-	go startDiscoveryBeacon()
+	go startDiscoveryBeacon(cfg.Discovery, cfg.Network)
 
 	// gRPC Setup
-	lis, err := net.Listen("tcp", ":50051")
+	lis, err := net.Listen("tcp", cfg.Network.Listen)
 	if err != nil {
-		log.Fatalf("failed to listen: %v", err)
+		fatal("Failed to listen", err)
 	}
-
 	grpcSrv := grpc.NewServer()
 	pb.RegisterNexusServiceServer(grpcSrv, nexusSrv)
+	healthSrv := health.NewServer()
+	healthpb.RegisterHealthServer(grpcSrv, healthSrv)
+	reflection.Register(grpcSrv)
 
-	// Run gRPC server in background
+	// The admin API gets its own listener, on localhost unless configured otherwise
+	adminLis, err := net.Listen("tcp", cfg.Network.AdminListen)
+	if err != nil {
+		fatal("Failed to listen", err)
+	}
+	admin := &adminServer{s: nexusSrv}
+	adminSrv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(nexusSrv.unaryAuth),
+		grpc.ChainStreamInterceptor(nexusSrv.streamAuth),
+	)
+	pb.RegisterAdminServiceServer(adminSrv, admin)
+
+	// Run gRPC servers in background
 	go func() {
 		if err := grpcSrv.Serve(lis); err != nil {
-			log.Fatalf("gRPC serve error: %v", err)
+			fatal("gRPC serve error", err)
 		}
 	}()
-
-	// 6. UI Refresh (Your requested block)
 	go func() {
-		time.Sleep(100 * time.Millisecond)
-		nexusSrv.refreshUI()
+		if err := adminSrv.Serve(adminLis); err != nil {
+			fatal("Admin serve error", err)
+		}
 	}()
+	slog.Info("NexusOps is running", "listen", cfg.Network.Listen, "console", filepath.Base(os.Args[0])+" console -addr "+cfg.Network.AdminListen)
+
+	var webSrv *http.Server
+	if cfg.Network.HTTPListen != "" {
+		webSrv = &http.Server{Addr: cfg.Network.HTTPListen, Handler: newWebHandler(nexusSrv, admin)}
+		go serveWeb(webSrv)
+		slog.Info("Web dashboard started", "listen", cfg.Network.HTTPListen)
+	}
 
-	// 7. Run TUI (Blocking call)
-	if err := app.SetRoot(root, true).Run(); err != nil {
-		log.Fatal(err)
+	// Revenue is in the metrics, so they get their own listener, on localhost unless configured otherwise
+	var metricsSrv *http.Server
+	if cfg.Network.MetricsListen != "" {
+		mux := http.NewServeMux()
+		mux.Handle("GET /metrics", newMetricsHandler(nexusSrv))
+		metricsSrv = &http.Server{Addr: cfg.Network.MetricsListen, Handler: mux}
+		go serveWeb(metricsSrv)
+		slog.Info("Metrics started", "listen", cfg.Network.MetricsListen)
 	}
 
-	// 8. Graceful Shutdown (Runs after ESC is pressed)
-	log.Println("Shutting down NexusOps...")
+	go nexusSrv.runHousekeeping()
+	go nexusSrv.runHealthChecks(healthSrv)
+
+	// 4. Wait for Ctrl+C or the service manager
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	<-stop
+
+	// 5. Graceful Shutdown
+	slog.Info("Shutting down NexusOps")
 
 	if beaconServer != nil {
-		log.Println("Stopping mDNS Discovery...")
+		slog.Info("Stopping mDNS discovery")
 		beaconServer.Shutdown()
 	}
 
-	grpcSrv.GracefulStop()
+	// Sentry and console streams never end on their own, so don't wait for them forever
+	healthSrv.Shutdown() // Probes see us going away before the listeners close
+	stopWeb(webSrv)
+	stopWeb(metricsSrv)
+	stopGracefully(adminSrv, 2*time.Second)
+	stopGracefully(grpcSrv, 5*time.Second)
+
+	// Running sessions may be a flush behind
+	nexusSrv.mu.Lock()
+	nexusSrv.flushSessions()
+	nexusSrv.mu.Unlock()
 
 	// Correct way to close a GORM database:
 	if db != nil {
@@ -137,3 +190,16 @@ func main() {
 		}
 	}
 }
+
+func stopGracefully(srv *grpc.Server, timeout time.Duration) {
+	done := make(chan struct{})
+	go func() {
+		srv.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		srv.Stop()
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"gorm.io/gorm"
+)
+
+// Counters and histograms updated as things happen. Gauges of the current state are
+// read under s.mu at scrape time by stateCollector instead.
+var (
+	heartbeatsTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "nexusops", Name: "heartbeats_total",
+		Help: "Heartbeats received from Sentries.",
+	})
+	streamReconnects = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "nexusops", Name: "stream_reconnects_total",
+		Help: "Streams opened by PCs that had connected before.",
+	})
+	commandLatency = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "nexusops", Name: "command_latency_seconds",
+		Help:    "Time from queueing a command for a PC to handing it to its stream.",
+		Buckets: []float64{.001, .005, .01, .05, .1, .5, 1, 5},
+	})
+	dbWriteLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "nexusops", Name: "db_write_seconds",
+		Help:    "Time spent in database writes, by table.",
+		Buckets: []float64{.001, .005, .01, .05, .1, .5, 1, 5},
+	}, []string{"table"})
+	revenueTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "nexusops", Name: "revenue_total",
+		Help: "Money taken since the server started, by payment method.",
+	}, []string{"method"})
+	paymentsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "nexusops", Name: "payments_total",
+		Help: "Payments recorded since the server started, by payment method.",
+	}, []string{"method"})
+)
+
+var (
+	connectedDesc = prometheus.NewDesc("nexusops_connected_stations",
+		"PCs with an open stream.", nil, nil)
+	activeSessionsDesc = prometheus.NewDesc("nexusops_active_sessions",
+		"Sessions being billed right now, by game.", []string{"game"}, nil)
+	pendingGamesDesc = prometheus.NewDesc("nexusops_pending_games",
+		"Executables waiting to be classified.", nil, nil)
+)
+
+// stateCollector reports the server's live state on every scrape.
+type stateCollector struct {
+	s *server
+}
+
+func (c stateCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- connectedDesc
+	ch <- activeSessionsDesc
+	ch <- pendingGamesDesc
+}
+
+func (c stateCollector) Collect(ch chan<- prometheus.Metric) {
+	c.s.mu.Lock()
+	defer c.s.mu.Unlock()
+
+	ch <- prometheus.MustNewConstMetric(connectedDesc, prometheus.GaugeValue, float64(len(c.s.outbox)))
+	ch <- prometheus.MustNewConstMetric(pendingGamesDesc, prometheus.GaugeValue, float64(c.s.pendingCount()))
+	byGame := make(map[string]int)
+	for _, ls := range c.s.liveSessions {
+		byGame[ls.sess.DisplayName()]++
+	}
+	for game, n := range byGame {
+		ch <- prometheus.MustNewConstMetric(activeSessionsDesc, prometheus.GaugeValue, float64(n), game)
+	}
+}
+
+// newMetricsHandler serves /metrics, including the Go runtime and process metrics that
+// show a Pi running out of memory or file descriptors.
+func newMetricsHandler(s *server) http.Handler {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		stateCollector{s},
+		heartbeatsTotal, streamReconnects, commandLatency, dbWriteLatency, revenueTotal, paymentsTotal,
+	)
+	return promhttp.HandlerFor(reg, promhttp.HandlerOpts{})
+}
+
+// timeDBWrites hooks gorm so every create, update and delete lands in db_write_seconds.
+func timeDBWrites(db *gorm.DB) error {
+	const startKey = "metrics:start"
+	before := func(tx *gorm.DB) {
+		tx.InstanceSet(startKey, time.Now())
+	}
+	after := func(tx *gorm.DB) {
+		if start, ok := tx.InstanceGet(startKey); ok {
+			dbWriteLatency.WithLabelValues(tx.Statement.Table).Observe(time.Since(start.(time.Time)).Seconds())
+		}
+	}
+
+	cb := db.Callback()
+	for _, err := range []error{
+		cb.Create().Before("gorm:create").Register("metrics:before_create", before),
+		cb.Create().After("gorm:create").Register("metrics:after_create", after),
+		cb.Update().Before("gorm:update").Register("metrics:before_update", before),
+		cb.Update().After("gorm:update").Register("metrics:after_update", after),
+		cb.Delete().Before("gorm:delete").Register("metrics:before_delete", before),
+		cb.Delete().After("gorm:delete").Register("metrics:after_delete", after),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
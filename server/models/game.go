@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// Game maps a raw executable name reported by the Sentry to a friendly title.
+type Game struct {
+	Executable string `gorm:"primaryKey;type:varchar(260)"` // Lowercase image name, e.g. cs2.exe, or full path
+	Title      string
+	Genre      string
+	RateClass  string // Key into the configured hourly rates
+	Classified bool   `gorm:"index"` // False while waiting for an operator to name it
+	NotGame    bool   // Classified as a tool or launcher: clients ignore it instead of billing it
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
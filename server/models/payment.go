@@ -0,0 +1,28 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"time"
+)
+
+// Payment groups the sessions a customer settled at the counter.
+type Payment struct {
+	ID        string          `gorm:"primaryKey;type:varchar(36)" json:"id"`
+	PcID      string          `gorm:"index" json:"pc_id"`
+	Subtotal  decimal.Decimal `gorm:"type:decimal(20,2)" json:"subtotal"`
+	Discount  decimal.Decimal `gorm:"type:decimal(20,2)" json:"discount"`
+	Tax       decimal.Decimal `gorm:"type:decimal(20,2)" json:"tax"`
+	Total     decimal.Decimal `gorm:"type:decimal(20,2)" json:"total"`
+	Method    string          `json:"method"`   // cash, card, ...
+	Operator  string          `json:"operator"` // Who took the money
+	CreatedAt time.Time       `gorm:"index" json:"created_at"`
+}
+
+func (p *Payment) BeforeCreate(tx *gorm.DB) (err error) {
+	if p.ID == "" { // Imports keep the ID they were exported with
+		p.ID = uuid.New().String()
+	}
+	return
+}
@@ -8,20 +8,35 @@ import (
 )
 
 type Session struct {
-	ID              string `gorm:"primaryKey;type:varchar(36)"`
-	PcID            string `gorm:"index"`
-	GameName        string
-	StartTime       time.Time
-	EndTime         time.Time
-	DurationMinutes int
-	Fee             decimal.Decimal `gorm:"type:decimal(20,2)"`
-	IsActive        bool            `gorm:"index"`
-	Paid            bool            `gorm:"default:false;index"` // Track if customer paid
-	PaymentTime     *time.Time      // Store when they paid
-	CreatedAt       time.Time
+	ID              string          `gorm:"primaryKey;type:varchar(36)" json:"id"`
+	PcID            string          `gorm:"index" json:"pc_id"`
+	GameName        string          `json:"game_name"`  // Raw executable reported by the client
+	GamePath        string          `json:"game_path"`  // Its full image path, when the client could read it
+	GameTitle       string          `json:"game_title"` // Friendly name from the game catalog
+	RateClass       string          `json:"rate_class"`
+	StartTime       time.Time       `json:"start_time"`
+	EndTime         time.Time       `json:"end_time"`
+	DurationMinutes int             `json:"duration_minutes"` // Billed minutes, idle pauses excluded
+	PausedSeconds   int             `json:"paused_seconds"`   // Time billing was paused because nobody touched the PC
+	Fee             decimal.Decimal `gorm:"type:decimal(20,2)" json:"fee"`
+	IsActive        bool            `gorm:"index" json:"is_active"`
+	Paid            bool            `gorm:"default:false;index" json:"paid"` // Track if customer paid
+	PaymentTime     *time.Time      `json:"payment_time"`                    // Store when they paid
+	PaymentID       string          `gorm:"index" json:"payment_id"`         // The Payment that settled this session
+	CreatedAt       time.Time       `json:"created_at"`
 }
 
 func (s *Session) BeforeCreate(tx *gorm.DB) (err error) {
-	s.ID = uuid.New().String()
+	if s.ID == "" { // Imports keep the ID they were exported with
+		s.ID = uuid.New().String()
+	}
 	return
 }
+
+// DisplayName prefers the catalog title over the raw executable.
+func (s *Session) DisplayName() string {
+	if s.GameTitle != "" {
+		return s.GameTitle
+	}
+	return s.GameName
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// Station is a client PC the server has seen at least once, keyed by the PC ID it reports.
+type Station struct {
+	ID               string     `gorm:"primaryKey;type:varchar(64)" json:"id"`
+	IdleWarnMinutes  *int       `json:"idle_warn_minutes"` // nil falls back to the server-wide idle policy
+	IdlePauseMinutes *int       `json:"idle_pause_minutes"`
+	IdleLockMinutes  *int       `json:"idle_lock_minutes"`
+	Open             bool       `json:"open"`          // Unlocked by the operator until the PC is settled
+	PrepaidUntil     *time.Time `json:"prepaid_until"` // Unlocked until then, on top of Open
+	LastSeen         time.Time  `json:"last_seen"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+// Page sizes for the list calls. The cap keeps one call from pulling the whole history.
+const (
+	defaultPageSize = 100
+	maxPageSize     = 1000
+)
+
+// pageSize applies the default and the cap to a requested page size.
+func pageSize(limit int32) (int, error) {
+	switch {
+	case limit < 0:
+		return 0, status.Error(codes.InvalidArgument, "limit can't be negative")
+	case limit == 0:
+		return defaultPageSize, nil
+	case limit > maxPageSize:
+		return maxPageSize, nil
+	}
+	return int(limit), nil
+}
+
+// pageToken marks where the next page starts in a newest-first listing. It holds the
+// time and ID of the last row returned, so rows added meanwhile don't shift the pages.
+func pageToken(t time.Time, id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d|%s", t.UnixNano(), id)))
+}
+
+// afterPage narrows a newest-first query on column to the rows after the token.
+func afterPage(q *gorm.DB, column, token string) (*gorm.DB, error) {
+	if token == "" {
+		return q, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid page_token")
+	}
+	nanos, id, ok := strings.Cut(string(raw), "|")
+	n, err := strconv.ParseInt(nanos, 10, 64)
+	if !ok || err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid page_token")
+	}
+	t := time.Unix(0, n)
+	return q.Where(column+" < ? OR ("+column+" = ? AND id < ?)", t, t, id), nil
+}
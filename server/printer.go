@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Printer is anything that can turn a Receipt into paper (or a file).
+type Printer interface {
+	Print(r *Receipt) error
+}
+
+// newPrinter builds the backend selected in the config. A nil Printer means printing is off.
+func newPrinter(cfg PrinterConfig, language string) (Printer, error) {
+	switch cfg.Backend {
+	case "", "none":
+		return nil, nil
+	case "escpos":
+		// We send text as UTF-8 with no code page and no Arabic-script shaping, so a
+		// thermal printer would print Persian as garbage
+		if language == "fa" {
+			return nil, fmt.Errorf("escpos printer can't print Persian receipts; use the text backend or language \"en\"")
+		}
+		if cfg.Address != "" {
+			return &escposPrinter{width: cfg.Width, open: func() (io.WriteCloser, error) {
+				return net.DialTimeout("tcp", cfg.Address, 3*time.Second)
+			}}, nil
+		}
+		if cfg.Device != "" {
+			return &escposPrinter{width: cfg.Width, open: func() (io.WriteCloser, error) {
+				return os.OpenFile(cfg.Device, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+			}}, nil
+		}
+		return nil, fmt.Errorf("escpos printer needs a device or an address")
+	case "text":
+		return &textPrinter{dir: resolvePath(cfg.OutputDir), width: cfg.Width}, nil
+	}
+	return nil, fmt.Errorf("unknown printer backend %q", cfg.Backend)
+}
+
+// ESC/POS control sequences understood by practically every thermal printer.
+var (
+	escInit       = []byte{0x1B, 0x40}                         // ESC @
+	escAlignLeft  = []byte{0x1B, 0x61, 0x00}                   // ESC a 0
+	escBoldOn     = []byte{0x1B, 0x45, 0x01}                   // ESC E 1
+	escBoldOff    = []byte{0x1B, 0x45, 0x00}                   // ESC E 0
+	escFeedAndCut = []byte{0x1B, 0x64, 0x04, 0x1D, 0x56, 0x01} // ESC d 4, GS V 1 (partial cut)
+)
+
+// escposPrinter writes raw ESC/POS to a device node, a spool file or a TCP port (usually 9100).
+type escposPrinter struct {
+	width int
+	open  func() (io.WriteCloser, error)
+}
+
+func (p *escposPrinter) Print(r *Receipt) error {
+	w, err := p.open()
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	lines := r.Render(p.width)
+	buf := append([]byte{}, escInit...)
+	buf = append(buf, escAlignLeft...)
+	for i, line := range lines {
+		// The cafe name is the first line; make it stand out
+		if i == 0 {
+			buf = append(buf, escBoldOn...)
+			buf = append(buf, line...)
+			buf = append(buf, escBoldOff...)
+		} else {
+			buf = append(buf, line...)
+		}
+		buf = append(buf, '\n')
+	}
+	buf = append(buf, escFeedAndCut...)
+
+	_, err = w.Write(buf)
+	return err
+}
+
+// textPrinter drops each receipt into a .txt file, handy without hardware and for archiving.
+type textPrinter struct {
+	dir   string
+	width int
+}
+
+func (p *textPrinter) Print(r *Receipt) error {
+	if err := os.MkdirAll(p.dir, 0o755); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s_%s_%s.txt", r.Time.Format("20060102-150405"), r.PcID, r.Number)
+	return os.WriteFile(filepath.Join(p.dir, name), []byte(r.Text(p.width)), 0o644)
+}
@@ -0,0 +1,26 @@
+package main
+
+import "testing"
+
+func TestNewPrinter(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      PrinterConfig
+		language string
+		wantErr  bool
+		wantNil  bool
+	}{
+		{name: "off", cfg: PrinterConfig{Backend: "none"}, language: "fa", wantNil: true},
+		{name: "escpos in English", cfg: PrinterConfig{Backend: "escpos", Address: "192.168.1.50:9100"}, language: "en"},
+		{name: "escpos in Persian", cfg: PrinterConfig{Backend: "escpos", Address: "192.168.1.50:9100"}, language: "fa", wantErr: true, wantNil: true},
+		{name: "escpos without a target", cfg: PrinterConfig{Backend: "escpos"}, language: "en", wantErr: true, wantNil: true},
+		{name: "text in Persian", cfg: PrinterConfig{Backend: "text", OutputDir: t.TempDir()}, language: "fa"},
+		{name: "unknown", cfg: PrinterConfig{Backend: "pdf"}, language: "en", wantErr: true, wantNil: true},
+	}
+	for _, tt := range tests {
+		p, err := newPrinter(tt.cfg, tt.language)
+		if (err != nil) != tt.wantErr || (p == nil) != tt.wantNil {
+			t.Errorf("%s: newPrinter() = %v, %v; want error %v, nil printer %v", tt.name, p, err, tt.wantErr, tt.wantNil)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Mohammad-Mahdi82/NexusOps/server/models"
+	"github.com/shopspring/decimal"
+)
+
+// ReceiptLine is one itemized row: a play session today, products later.
+type ReceiptLine struct {
+	Description string
+	Detail      string
+	Amount      decimal.Decimal
+}
+
+type Receipt struct {
+	CafeName   string
+	Number     string
+	PcID       string
+	Time       time.Time
+	Lines      []ReceiptLine
+	Subtotal   decimal.Decimal
+	Discount   decimal.Decimal
+	TaxPercent float64
+	Tax        decimal.Decimal
+	Total      decimal.Decimal
+	Method     string
+	Operator   string
+	Footer     string
+
+	loc *Locale
+}
+
+// buildReceipt itemizes the sessions settled by a payment.
+func buildReceipt(cfg ReceiptConfig, loc *Locale, payment models.Payment, sessions []models.Session) *Receipt {
+	r := &Receipt{
+		CafeName:   cfg.CafeName,
+		Number:     strings.ToUpper(payment.ID[:8]),
+		PcID:       payment.PcID,
+		Time:       payment.CreatedAt,
+		Subtotal:   payment.Subtotal,
+		Discount:   payment.Discount,
+		TaxPercent: cfg.TaxPercent,
+		Tax:        payment.Tax,
+		Total:      payment.Total,
+		Method:     payment.Method,
+		Operator:   payment.Operator,
+		Footer:     cfg.Footer,
+		loc:        loc,
+	}
+	for _, sess := range sessions {
+		r.Lines = append(r.Lines, ReceiptLine{
+			Description: sess.DisplayName(),
+			Detail:      loc.Digits(sess.StartTime.Format("15:04")) + "  " + loc.T("receipt.minutes", sess.DurationMinutes),
+			Amount:      sess.Fee,
+		})
+	}
+	return r
+}
+
+// Render lays the receipt out as fixed-width text lines for the given paper width.
+// Right-to-left locales get their labels on the right and amounts on the left.
+func (r *Receipt) Render(width int) []string {
+	if width < 24 {
+		width = 24
+	}
+	rule := strings.Repeat("-", width)
+	loc := r.loc
+	row := func(label, value string) string {
+		if loc.RTL() {
+			return columns(value, label, width)
+		}
+		return columns(label, value, width)
+	}
+	money := func(d decimal.Decimal) string { return loc.Digits(d.StringFixed(0)) }
+
+	var out []string
+	out = append(out, center(r.CafeName, width), rule)
+	out = append(out, row(loc.T("receipt.number"), "#"+r.Number))
+	out = append(out, row(loc.T("receipt.pc"), r.PcID))
+	out = append(out, row(loc.T("receipt.date"), loc.DateTime(r.Time)))
+	out = append(out, rule)
+
+	for _, l := range r.Lines {
+		out = append(out, row(l.Description, money(l.Amount)))
+		if l.Detail != "" {
+			out = append(out, row("  "+l.Detail, ""))
+		}
+	}
+
+	out = append(out, rule)
+	out = append(out, row(loc.T("receipt.subtotal"), money(r.Subtotal)))
+	if !r.Discount.IsZero() {
+		out = append(out, row(loc.T("receipt.discount"), "-"+money(r.Discount)))
+	}
+	if !r.Tax.IsZero() {
+		out = append(out, row(loc.T("receipt.tax", loc.Digits(fmt.Sprintf("%g", r.TaxPercent))), money(r.Tax)))
+	}
+	out = append(out, row(loc.T("receipt.total"), money(r.Total)))
+	out = append(out, rule)
+	out = append(out, row(loc.T("receipt.paid_by"), loc.Method(r.Method)))
+	out = append(out, row(loc.T("receipt.operator"), r.Operator))
+	if r.Footer != "" {
+		out = append(out, "", center(r.Footer, width))
+	}
+	return out
+}
+
+func (r *Receipt) Text(width int) string {
+	return strings.Join(r.Render(width), "\n") + "\n"
+}
+
+// columns puts left and right on one line, truncating the left side if they don't fit.
+func columns(left, right string, width int) string {
+	room := width - len([]rune(right))
+	if right != "" {
+		room-- // Keep at least one space between the columns
+	}
+	if room < 1 {
+		return right
+	}
+	l := []rune(left)
+	if len(l) > room {
+		l = l[:room]
+	}
+	return string(l) + strings.Repeat(" ", width-len(l)-len([]rune(right))) + right
+}
+
+func center(text string, width int) string {
+	n := len([]rune(text))
+	if n >= width {
+		return text
+	}
+	return strings.Repeat(" ", (width-n)/2) + text
+}
@@ -0,0 +1,299 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	pb "github.com/Mohammad-Mahdi82/NexusOps/pkg/monitor"
+	"github.com/Mohammad-Mahdi82/NexusOps/server/models"
+	"github.com/shopspring/decimal"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// defaultReportDays is the range of a report that doesn't say where to start.
+const defaultReportDays = 30
+
+// maxReportDays keeps one report from walking years of sessions hour by hour.
+const maxReportDays = 3 * 366
+
+// reportGroup accumulates one ReportRow.
+type reportGroup struct {
+	revenue  decimal.Decimal
+	payments int32
+	sessions int32
+	minutes  int32
+}
+
+// reportGroups is one way of slicing a report, keyed by the row's key.
+type reportGroups map[string]*reportGroup
+
+func (g reportGroups) at(key string) *reportGroup {
+	if g[key] == nil {
+		g[key] = &reportGroup{}
+	}
+	return g[key]
+}
+
+// rows sorts the groups by key, or by revenue and then minutes when byRevenue is set.
+func (g reportGroups) rows(byRevenue bool) []*pb.ReportRow {
+	keys := make([]string, 0, len(g))
+	for k := range g {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := g[keys[i]], g[keys[j]]
+		if byRevenue && !a.revenue.Equal(b.revenue) {
+			return a.revenue.GreaterThan(b.revenue)
+		}
+		if byRevenue && a.minutes != b.minutes {
+			return a.minutes > b.minutes
+		}
+		return keys[i] < keys[j]
+	})
+	rows := make([]*pb.ReportRow, 0, len(keys))
+	for _, k := range keys {
+		r := g[k]
+		rows = append(rows, &pb.ReportRow{Key: k, Revenue: r.revenue.String(), Payments: r.payments, Sessions: r.sessions, Minutes: r.minutes})
+	}
+	return rows
+}
+
+// report reads the payments and sessions of [from, to) and totals them every way at
+// once. It only reads the DB, so it doesn't hold s.mu; running sessions count as of
+// their last flush.
+func (s *server) report(from, to time.Time, period pb.ReportRequest_Period) (*pb.Report, error) {
+	byPeriod, byPC, byGame, byOperator := reportGroups{}, reportGroups{}, reportGroups{}, reportGroups{}
+	rep := &pb.Report{From: from.Unix(), To: to.Unix()}
+	jalali := s.loc.jalali
+
+	var payments []models.Payment
+	if err := s.db.Where("created_at >= ? AND created_at < ?", from, to).Find(&payments).Error; err != nil {
+		return nil, fmt.Errorf("payments not read: %w", err)
+	}
+	revenue := decimal.Zero
+	for _, p := range payments {
+		revenue = revenue.Add(p.Total)
+		operator := p.Operator
+		if operator == "" {
+			operator = "-"
+		}
+		for _, g := range []*reportGroup{byPeriod.at(periodKey(p.CreatedAt, period, jalali)), byPC.at(p.PcID), byOperator.at(operator)} {
+			g.revenue = g.revenue.Add(p.Total)
+			g.payments++
+		}
+	}
+	rep.Revenue = revenue.String()
+	rep.Payments = int32(len(payments))
+
+	// A payment settles whole sessions, so its total is split over their games by fee.
+	// Discounts and tax land where they were earned and the games add up to the revenue.
+	var settled []models.Session
+	paid := s.db.Model(&models.Payment{}).Select("id").Where("created_at >= ? AND created_at < ?", from, to)
+	if err := s.db.Where("payment_id IN (?)", paid).Order("start_time").Find(&settled).Error; err != nil {
+		return nil, fmt.Errorf("paid sessions not read: %w", err)
+	}
+	bySettlement := make(map[string][]models.Session)
+	for _, sess := range settled {
+		bySettlement[sess.PaymentID] = append(bySettlement[sess.PaymentID], sess)
+	}
+	for _, p := range payments {
+		games := bySettlement[p.ID]
+		if len(games) == 0 {
+			g := byGame.at("-") // Sessions deleted since, or a payment with nothing behind it
+			g.revenue = g.revenue.Add(p.Total)
+			g.payments++
+			continue
+		}
+		fees := make([]decimal.Decimal, len(games))
+		for i, sess := range games {
+			fees[i] = sess.Fee
+		}
+		counted := make(map[string]bool)
+		for i, share := range splitTotal(p.Total, fees) {
+			name := games[i].DisplayName()
+			g := byGame.at(name)
+			g.revenue = g.revenue.Add(share)
+			if !counted[name] {
+				g.payments++
+				counted[name] = true
+			}
+		}
+	}
+
+	// Sessions that started before the range still fill its first hours
+	var sessions []models.Session
+	if err := s.db.Where("start_time < ? AND (is_active = ? OR end_time > ?)", to, true, from).Find(&sessions).Error; err != nil {
+		return nil, fmt.Errorf("sessions not read: %w", err)
+	}
+	var busy [7][24]float64
+	var ended, endedMinutes int
+	now := time.Now()
+	for _, sess := range sessions {
+		end := sess.EndTime
+		if sess.IsActive {
+			end = now
+		}
+		addBusyTime(&busy, maxTime(sess.StartTime, from), minTime(end, to))
+
+		if sess.StartTime.Before(from) {
+			continue
+		}
+		minutes := int32(sess.DurationMinutes)
+		rep.Sessions++
+		rep.Minutes += minutes
+		if !sess.IsActive {
+			ended++
+			endedMinutes += sess.DurationMinutes
+		}
+		for _, g := range []*reportGroup{byPeriod.at(periodKey(sess.StartTime, period, jalali)), byPC.at(sess.PcID)} {
+			g.sessions++
+			g.minutes += minutes
+		}
+		g := byGame.at(sess.DisplayName())
+		g.sessions++
+		g.minutes += minutes
+	}
+	if ended > 0 {
+		rep.AverageSessionMinutes = float64(endedMinutes) / float64(ended)
+	}
+
+	var stations int64
+	if err := s.db.Model(&models.Station{}).Count(&stations).Error; err != nil {
+		return nil, fmt.Errorf("stations not read: %w", err)
+	}
+	rep.Stations = int32(stations)
+	occurrences := hourOccurrences(from, to)
+	for day := range 7 {
+		for hour := range 24 {
+			cell := &pb.HourUsage{Weekday: int32(day), Hour: int32(hour), Minutes: int32(busy[day][hour] + 0.5)}
+			if capacity := float64(stations) * 60 * float64(occurrences[day][hour]); capacity > 0 {
+				cell.Utilization = min(busy[day][hour]/capacity, 1)
+			}
+			rep.Hours = append(rep.Hours, cell)
+		}
+	}
+
+	rep.ByPeriod = byPeriod.rows(false)
+	rep.ByPc = byPC.rows(true)
+	rep.ByGame = byGame.rows(true)
+	rep.ByOperator = byOperator.rows(true)
+	return rep, nil
+}
+
+// splitTotal divides total over the weights in proportion, rounded to cents. The last
+// share takes what rounding left over, so the shares always add up to total; when the
+// weights are all zero the split is even.
+func splitTotal(total decimal.Decimal, weights []decimal.Decimal) []decimal.Decimal {
+	sum := decimal.Zero
+	for _, w := range weights {
+		sum = sum.Add(w)
+	}
+	shares := make([]decimal.Decimal, len(weights))
+	left := total
+	for i, w := range weights {
+		if i == len(weights)-1 {
+			shares[i] = left
+			break
+		}
+		share := total.Div(decimal.NewFromInt(int64(len(weights))))
+		if !sum.IsZero() {
+			share = total.Mul(w).Div(sum)
+		}
+		shares[i] = share.Round(2)
+		left = left.Sub(shares[i])
+	}
+	return shares
+}
+
+// periodKey names the day, week or month t falls in, by its first day. Keys of one
+// period kind sort in time order.
+func periodKey(t time.Time, period pb.ReportRequest_Period, jalali bool) string {
+	day := startOfDay(t)
+	switch period {
+	case pb.ReportRequest_WEEK:
+		first := time.Monday
+		if jalali {
+			first = time.Saturday
+		}
+		back := (int(day.Weekday()) - int(first) + 7) % 7
+		day = day.AddDate(0, 0, -back)
+	case pb.ReportRequest_MONTH:
+		if jalali {
+			jy, jm, _ := gregorianToJalali(day.Year(), int(day.Month()), day.Day())
+			return fmt.Sprintf("%04d/%02d", jy, jm)
+		}
+		return day.Format("2006-01")
+	}
+	if jalali {
+		jy, jm, jd := gregorianToJalali(day.Year(), int(day.Month()), day.Day())
+		return fmt.Sprintf("%04d/%02d/%02d", jy, jm, jd)
+	}
+	return day.Format("2006-01-02")
+}
+
+// hourStart is the start of t's hour on the local clock. Truncate would be off by half
+// an hour in zones like Tehran's.
+func hourStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location())
+}
+
+// addBusyTime spreads the minutes between start and end over the hours they fall in.
+func addBusyTime(busy *[7][24]float64, start, end time.Time) {
+	for t := start; t.Before(end); {
+		next := minTime(hourStart(t).Add(time.Hour), end)
+		busy[t.Weekday()][t.Hour()] += next.Sub(t).Minutes()
+		t = next
+	}
+}
+
+// hourOccurrences counts how often each weekday and hour occurs in [from, to).
+func hourOccurrences(from, to time.Time) [7][24]int {
+	var n [7][24]int
+	for t := hourStart(from); t.Before(to); t = t.Add(time.Hour) {
+		n[t.Weekday()][t.Hour()]++
+	}
+	return n
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func (a *adminServer) GetReport(_ context.Context, req *pb.ReportRequest) (*pb.Report, error) {
+	to := time.Now()
+	if req.To > 0 {
+		to = time.Unix(req.To, 0)
+	}
+	from := to.AddDate(0, 0, -defaultReportDays)
+	if req.From > 0 {
+		from = time.Unix(req.From, 0)
+	}
+	switch {
+	case !from.Before(to):
+		return nil, status.Error(codes.InvalidArgument, "from must be before to")
+	case to.Sub(from) > maxReportDays*24*time.Hour:
+		return nil, status.Errorf(codes.InvalidArgument, "a report covers at most %d days", maxReportDays)
+	}
+	if _, ok := pb.ReportRequest_Period_name[int32(req.Period)]; !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown period %v", req.Period)
+	}
+
+	rep, err := a.s.report(from, to, req.Period)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return rep, nil
+}
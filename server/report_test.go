@@ -0,0 +1,226 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	pb "github.com/Mohammad-Mahdi82/NexusOps/pkg/monitor"
+	"github.com/Mohammad-Mahdi82/NexusOps/server/models"
+	"github.com/shopspring/decimal"
+)
+
+func TestReportGameRevenue(t *testing.T) {
+	s := newTestServer(t)
+	to := time.Now()
+	from := to.Add(-24 * time.Hour)
+	start := to.Add(-5 * time.Hour)
+	paidAt := to.Add(-time.Hour)
+
+	// 40,000 billed, 4,000 off and 2,000 tax: 38,000 taken
+	mustCreate(t, s, &models.Payment{ID: "pay", PcID: "PC-01", Subtotal: decimal.NewFromInt(40000),
+		Discount: decimal.NewFromInt(4000), Tax: decimal.NewFromInt(2000), Total: decimal.NewFromInt(38000), CreatedAt: paidAt})
+	for _, sess := range []models.Session{
+		{ID: "cs2", PcID: "PC-01", GameName: "cs2.exe", GameTitle: "Counter-Strike 2", StartTime: start, EndTime: start.Add(time.Hour),
+			DurationMinutes: 60, Fee: decimal.NewFromInt(30000), Paid: true, PaymentID: "pay"},
+		{ID: "dota", PcID: "PC-01", GameName: "dota2.exe", StartTime: start.Add(time.Hour), EndTime: start.Add(2 * time.Hour),
+			DurationMinutes: 60, Fee: decimal.NewFromInt(10000), Paid: true, PaymentID: "pay"},
+		{ID: "unpaid", PcID: "PC-02", GameName: "valorant.exe", StartTime: start, EndTime: start.Add(time.Hour),
+			DurationMinutes: 60, Fee: decimal.NewFromInt(5000)},
+	} {
+		mustCreate(t, s, &sess)
+	}
+
+	rep, err := s.report(from, to, pb.ReportRequest_DAY)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]struct {
+		revenue  string
+		payments int32
+		sessions int32
+	}{
+		"Counter-Strike 2": {"28500", 1, 1},
+		"dota2.exe":        {"9500", 1, 1},
+		"valorant.exe":     {"0", 0, 1},
+	}
+	sum := decimal.Zero
+	for _, r := range rep.ByGame {
+		w, ok := want[r.Key]
+		if !ok || r.Revenue != w.revenue || r.Payments != w.payments || r.Sessions != w.sessions {
+			t.Errorf("row %v, want %+v", r, w)
+		}
+		sum = sum.Add(decimal.RequireFromString(r.Revenue))
+	}
+	if len(rep.ByGame) != len(want) {
+		t.Errorf("%d game rows, want %d", len(rep.ByGame), len(want))
+	}
+	if !sum.Equal(decimal.RequireFromString(rep.Revenue)) {
+		t.Errorf("games add up to %s, report revenue is %s", sum, rep.Revenue)
+	}
+}
+
+func TestSplitTotal(t *testing.T) {
+	d := decimal.RequireFromString
+	tests := []struct {
+		name    string
+		total   string
+		weights []string
+		want    []string
+	}{
+		{"proportional", "38000", []string{"30000", "10000"}, []string{"28500", "9500"}},
+		{"rounding left on the last share", "100", []string{"1", "1", "1"}, []string{"33.33", "33.33", "33.34"}},
+		{"zero weights split evenly", "90", []string{"0", "0"}, []string{"45", "45"}},
+		{"one share", "12.5", []string{"0"}, []string{"12.5"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			weights := make([]decimal.Decimal, len(tt.weights))
+			for i, w := range tt.weights {
+				weights[i] = d(w)
+			}
+			got := splitTotal(d(tt.total), weights)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Equal(d(tt.want[i])) {
+					t.Errorf("got %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+// tehran is a half-hour zone; a fixed one keeps the tests off the tz database.
+var tehran = time.FixedZone("IRST", 3*3600+1800)
+
+func TestPeriodKey(t *testing.T) {
+	at := func(loc *time.Location, y int, m time.Month, d, h, min int) time.Time {
+		return time.Date(y, m, d, h, min, 0, 0, loc)
+	}
+	tests := []struct {
+		name   string
+		t      time.Time
+		period pb.ReportRequest_Period
+		jalali bool
+		want   string
+	}{
+		{"day", at(time.UTC, 2026, 10, 19, 23, 59), pb.ReportRequest_DAY, false, "2026-10-19"},
+		{"day on the local clock", at(tehran, 2026, 10, 20, 0, 15), pb.ReportRequest_DAY, false, "2026-10-20"},
+		{"jalali day", at(time.UTC, 2026, 10, 19, 12, 0), pb.ReportRequest_DAY, true, "1405/07/27"},
+		{"week from its monday", at(time.UTC, 2026, 10, 19, 0, 0), pb.ReportRequest_WEEK, false, "2026-10-19"},
+		{"week from its sunday", at(time.UTC, 2026, 10, 25, 23, 0), pb.ReportRequest_WEEK, false, "2026-10-19"},
+		{"week across a month", at(time.UTC, 2026, 10, 1, 9, 0), pb.ReportRequest_WEEK, false, "2026-09-28"},
+		{"week across a year", at(time.UTC, 2027, 1, 1, 9, 0), pb.ReportRequest_WEEK, false, "2026-12-28"},
+		{"jalali week from its saturday", at(time.UTC, 2026, 10, 24, 9, 0), pb.ReportRequest_WEEK, true, "1405/08/02"},
+		{"jalali week from its friday", at(time.UTC, 2026, 10, 23, 9, 0), pb.ReportRequest_WEEK, true, "1405/07/25"},
+		{"last of a month", at(time.UTC, 2026, 10, 31, 23, 0), pb.ReportRequest_MONTH, false, "2026-10"},
+		{"first of a month", at(time.UTC, 2026, 11, 1, 0, 0), pb.ReportRequest_MONTH, false, "2026-11"},
+		{"jalali month before nowruz", at(time.UTC, 2026, 3, 20, 12, 0), pb.ReportRequest_MONTH, true, "1404/12"},
+		{"jalali month from nowruz", at(time.UTC, 2026, 3, 21, 0, 0), pb.ReportRequest_MONTH, true, "1405/01"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := periodKey(tt.t, tt.period, tt.jalali); got != tt.want {
+				t.Errorf("periodKey(%v) = %q, want %q", tt.t, got, tt.want)
+			}
+		})
+	}
+}
+
+// hourCell is one weekday and hour of the heatmap.
+type hourCell struct {
+	day  time.Weekday
+	hour int
+}
+
+func TestAddBusyTime(t *testing.T) {
+	monday := func(loc *time.Location, h, min int) time.Time {
+		return time.Date(2026, 10, 19, h, min, 0, 0, loc)
+	}
+	tests := []struct {
+		name       string
+		start, end time.Time
+		want       map[hourCell]float64
+	}{
+		{"within the hour", monday(time.UTC, 10, 10), monday(time.UTC, 10, 40),
+			map[hourCell]float64{{time.Monday, 10}: 30}},
+		{"over three hours", monday(time.UTC, 10, 30), monday(time.UTC, 12, 15),
+			map[hourCell]float64{{time.Monday, 10}: 30, {time.Monday, 11}: 60, {time.Monday, 12}: 15}},
+		{"half-hour zone", monday(tehran, 10, 30), monday(tehran, 11, 30),
+			map[hourCell]float64{{time.Monday, 10}: 30, {time.Monday, 11}: 30}},
+		{"across midnight into sunday", time.Date(2026, 10, 24, 23, 30, 0, 0, time.UTC), time.Date(2026, 10, 25, 0, 45, 0, 0, time.UTC),
+			map[hourCell]float64{{time.Saturday, 23}: 30, {time.Sunday, 0}: 45}},
+		{"ends before it starts", monday(time.UTC, 11, 0), monday(time.UTC, 10, 0), map[hourCell]float64{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var busy [7][24]float64
+			addBusyTime(&busy, tt.start, tt.end)
+			for day := range 7 {
+				for hour := range 24 {
+					if got, want := busy[day][hour], tt.want[hourCell{time.Weekday(day), hour}]; got != want {
+						t.Errorf("%v %02d:00 = %v minutes, want %v", time.Weekday(day), hour, got, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestHourOccurrences(t *testing.T) {
+	monday := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		from, to time.Time
+		all      int // Every cell not in want
+		want     map[hourCell]int
+	}{
+		{"one week", monday, monday.AddDate(0, 0, 7), 1, nil},
+		{"two weeks across a month", time.Date(2026, 10, 24, 0, 0, 0, 0, time.UTC), time.Date(2026, 11, 7, 0, 0, 0, 0, time.UTC), 2, nil},
+		{"across a month end", time.Date(2026, 10, 31, 22, 0, 0, 0, time.UTC), time.Date(2026, 11, 1, 2, 0, 0, 0, time.UTC), 0,
+			map[hourCell]int{{time.Saturday, 22}: 1, {time.Saturday, 23}: 1, {time.Sunday, 0}: 1, {time.Sunday, 1}: 1}},
+		{"half-hour zone", time.Date(2026, 10, 19, 10, 30, 0, 0, tehran), time.Date(2026, 10, 19, 12, 30, 0, 0, tehran), 0,
+			map[hourCell]int{{time.Monday, 10}: 1, {time.Monday, 11}: 1, {time.Monday, 12}: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := hourOccurrences(tt.from, tt.to)
+			for day := range 7 {
+				for hour := range 24 {
+					want, ok := tt.want[hourCell{time.Weekday(day), hour}]
+					if !ok {
+						want = tt.all
+					}
+					if n[day][hour] != want {
+						t.Errorf("%v %02d:00 occurs %d times, want %d", time.Weekday(day), hour, n[day][hour], want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestReportSessionBeforeRange(t *testing.T) {
+	s := newTestServer(t)
+	from := time.Date(2026, 10, 19, 10, 0, 0, 0, time.Local)
+	to := from.Add(24 * time.Hour)
+	mustCreate(t, s, &models.Session{ID: "early", PcID: "PC-01", GameName: "cs2.exe",
+		StartTime: from.Add(-2 * time.Hour), EndTime: from.Add(90 * time.Minute), DurationMinutes: 210, Fee: decimal.NewFromInt(50000)})
+
+	rep, err := s.report(from, to, pb.ReportRequest_DAY)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// It fills the range's first hours, but it started before the range so it isn't counted there
+	if rep.Sessions != 0 || rep.Minutes != 0 || len(rep.ByGame) != 0 {
+		t.Errorf("counted %d sessions, %d minutes, games %v", rep.Sessions, rep.Minutes, rep.ByGame)
+	}
+	want := map[hourCell]int32{{from.Weekday(), 10}: 60, {from.Weekday(), 11}: 30}
+	for _, h := range rep.Hours {
+		if got := want[hourCell{time.Weekday(h.Weekday), int(h.Hour)}]; h.Minutes != got {
+			t.Errorf("%v %02d:00 = %d minutes, want %d", time.Weekday(h.Weekday), h.Hour, h.Minutes, got)
+		}
+	}
+}
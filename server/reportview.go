@@ -0,0 +1,155 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	pb "github.com/Mohammad-Mahdi82/NexusOps/pkg/monitor"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// How many days, weeks and months the report screen shows, today included.
+const (
+	reportScreenDays   = 30
+	reportScreenWeeks  = 12
+	reportScreenMonths = 12
+)
+
+// reportRanges is how far back each grouping of the report screen looks.
+var reportRanges = map[pb.ReportRequest_Period]func(today time.Time) time.Time{
+	pb.ReportRequest_DAY:   func(today time.Time) time.Time { return today.AddDate(0, 0, 1-reportScreenDays) },
+	pb.ReportRequest_WEEK:  func(today time.Time) time.Time { return today.AddDate(0, 0, 1-7*reportScreenWeeks) },
+	pb.ReportRequest_MONTH: func(today time.Time) time.Time { return today.AddDate(0, -reportScreenMonths, 1) },
+}
+
+// showReport fetches a report up to the end of today and opens it over the dashboard.
+func (c *console) showReport(period pb.ReportRequest_Period) {
+	today := startOfDay(time.Now())
+	req := &pb.ReportRequest{
+		From:   reportRanges[period](today).Unix(),
+		To:     today.AddDate(0, 0, 1).Unix(),
+		Period: period,
+	}
+	go func() {
+		ctx, cancel := c.rpcContext()
+		defer cancel()
+		rep, err := c.admin.GetReport(ctx, req)
+		c.app.QueueUpdateDraw(func() {
+			if err != nil {
+				c.setStatus(err)
+				c.refreshUI()
+				return
+			}
+			c.pages.AddPage("report", c.reportPage(rep), true, true)
+		})
+	}()
+}
+
+// reportPage lays out a report: totals, the four groupings side by side and the
+// hour-of-day heatmap. D, W and M reload it with another grouping.
+func (c *console) reportPage(rep *pb.Report) tview.Primitive {
+	avg := int(rep.AverageSessionMinutes + 0.5)
+	summary := tview.NewTextView().SetTextAlign(tview.AlignCenter).
+		SetText(c.loc.T("report.summary", c.loc.Digits(money(rep.Revenue)), rep.Payments, rep.Sessions, rep.Minutes, avg))
+
+	tables := []*tview.Table{
+		c.reportTable("report.period", rep.ByPeriod),
+		c.reportTable("report.pc", rep.ByPc),
+		c.reportTable("report.game", rep.ByGame),
+		c.reportTable("report.operator", rep.ByOperator),
+	}
+	groups := tview.NewFlex().SetDirection(tview.FlexColumn)
+	for _, t := range tables {
+		groups.AddItem(t, 0, 1, t == tables[0])
+	}
+
+	hint := tview.NewTextView().SetTextAlign(tview.AlignCenter).SetTextColor(tcell.ColorYellow).
+		SetText(c.loc.T("report.hint", reportScreenDays, reportScreenWeeks, reportScreenMonths))
+
+	page := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(summary, 1, 0, false).
+		AddItem(groups, 0, 1, true).
+		AddItem(c.heatmap(rep), 10, 0, false). // Hours, seven days and the border
+		AddItem(hint, 1, 0, false)
+	last := time.Unix(rep.To, 0).Add(-time.Second)
+	page.SetBorder(true).SetTitle(c.loc.T("report.title", c.loc.Date(time.Unix(rep.From, 0)), c.loc.Date(last)))
+
+	page.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case 'd', 'D':
+			c.showReport(pb.ReportRequest_DAY)
+		case 'w', 'W':
+			c.showReport(pb.ReportRequest_WEEK)
+		case 'm', 'M':
+			c.showReport(pb.ReportRequest_MONTH)
+		default:
+			if event.Key() != tcell.KeyTab {
+				return event
+			}
+			for i, t := range tables {
+				if t.HasFocus() {
+					c.app.SetFocus(tables[(i+1)%len(tables)])
+					break
+				}
+			}
+		}
+		return nil
+	})
+	return page
+}
+
+// reportTable lists one grouping of a report, scrollable when it has focus.
+func (c *console) reportTable(title string, rows []*pb.ReportRow) *tview.Table {
+	t := tview.NewTable().SetFixed(1, 0).SetSelectable(true, false)
+	t.SetBorder(true).SetTitle(" " + c.loc.T(title) + " ")
+	t.SetFocusFunc(func() { t.SetBorderColor(tcell.ColorYellow) })
+	t.SetBlurFunc(func() { t.SetBorderColor(tcell.ColorWhite) })
+
+	for i, h := range []string{"", c.loc.T("col.revenue"), c.loc.T("col.min")} {
+		align := tview.AlignRight
+		if i == 0 {
+			align = tview.AlignLeft
+		}
+		t.SetCell(0, i, tview.NewTableCell(h).SetTextColor(tcell.ColorYellow).SetAttributes(tcell.AttrBold).SetAlign(align).SetSelectable(false))
+	}
+	for i, r := range rows {
+		t.SetCell(i+1, 0, tview.NewTableCell(c.loc.Digits(r.Key)).SetExpansion(1).SetMaxWidth(20))
+		t.SetCell(i+1, 1, tview.NewTableCell(c.loc.Digits(money(r.Revenue))).SetAlign(tview.AlignRight))
+		t.SetCell(i+1, 2, tview.NewTableCell(c.loc.Digits(fmt.Sprint(r.Minutes))).SetAlign(tview.AlignRight))
+	}
+	return t
+}
+
+// heatmap shows utilization per weekday and hour, greener the busier, with the week
+// starting the way the configured calendar starts it.
+func (c *console) heatmap(rep *pb.Report) *tview.Table {
+	t := tview.NewTable()
+	t.SetBorder(true).SetTitle(" " + c.loc.T("report.heatmap") + " ")
+	if len(rep.Hours) != 7*24 {
+		return t
+	}
+
+	days := []int{1, 2, 3, 4, 5, 6, 0}
+	if c.loc.jalali {
+		days = []int{6, 0, 1, 2, 3, 4, 5}
+	}
+	for hour := range 24 {
+		t.SetCell(0, hour+1, tview.NewTableCell(c.loc.Digits(fmt.Sprintf("%02d", hour))).SetTextColor(tcell.ColorYellow))
+	}
+	for row, day := range days {
+		t.SetCell(row+1, 0, tview.NewTableCell(c.loc.T(fmt.Sprintf("weekday.%d", day))).SetTextColor(tcell.ColorYellow))
+		for hour := range 24 {
+			u := rep.Hours[day*24+hour].Utilization
+			cell := tview.NewTableCell(c.loc.Digits(fmt.Sprintf("%2.0f", u*100))).SetAlign(tview.AlignRight)
+			if u > 0 {
+				green := int32(60 + 160*u)
+				cell.SetBackgroundColor(tcell.NewRGBColor(0, green, 0)).SetTextColor(tcell.ColorBlack)
+			} else {
+				cell.SetTextColor(tcell.ColorGray)
+			}
+			t.SetCell(row+1, hour+1, cell)
+		}
+	}
+	return t
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	_ "embed"
+	"io"
+	"net/http"
+	"strconv"
+
+	pb "github.com/Mohammad-Mahdi82/NexusOps/pkg/monitor"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// openAPISpec documents the /v1 routes. Keep it in step with registerREST.
+//
+//go:embed openapi.json
+var openAPISpec []byte
+
+// registerREST mounts the versioned REST/JSON API for tools that can't speak gRPC. Every
+// route is a thin wrapper over an AdminService call, so the two never disagree. Bodies
+// and replies use the proto JSON mapping: camelCase names and 64-bit numbers as strings.
+func (w *webServer) registerREST(mux *http.ServeMux) {
+	mux.HandleFunc("GET /v1/openapi.json", func(rw http.ResponseWriter, _ *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		rw.Write(openAPISpec)
+	})
+
+	mux.HandleFunc("GET /v1/stations", w.authed(restCall(w.admin.ListStations, func(r *http.Request, req *pb.ListStationsRequest) (err error) {
+		req.ConnectedOnly, err = queryBool(r, "connected_only")
+		return err
+	})))
+	mux.HandleFunc("GET /v1/stations/{id}", w.authed(restCall(w.admin.GetStation, func(r *http.Request, req *pb.GetStationRequest) error {
+		req.Id = r.PathValue("id")
+		return nil
+	})))
+	mux.HandleFunc("POST /v1/stations/{id}/payments", w.authed(restCall(w.admin.RecordPayment, func(r *http.Request, req *pb.RecordPaymentRequest) error {
+		req.PcId = r.PathValue("id")
+		return nil
+	})))
+	mux.HandleFunc("POST /v1/stations/{id}/commands", w.authed(restCall(w.admin.SendCommand, func(r *http.Request, req *pb.SendCommandRequest) error {
+		req.PcId = r.PathValue("id")
+		return nil
+	})))
+	mux.HandleFunc("POST /v1/stations/{id}/timed-sessions", w.authed(restCall(w.admin.StartTimedSession, func(r *http.Request, req *pb.StartTimedSessionRequest) error {
+		req.PcId = r.PathValue("id")
+		return nil
+	})))
+
+	mux.HandleFunc("GET /v1/sessions", w.authed(restCall(w.admin.ListSessions, func(r *http.Request, req *pb.ListSessionsRequest) error {
+		q := r.URL.Query()
+		req.PcId, req.PageToken = q.Get("pc_id"), q.Get("page_token")
+		if q.Has("paid") {
+			paid, err := queryBool(r, "paid")
+			if err != nil {
+				return err
+			}
+			req.Paid = &paid
+		}
+		return queryRange(r, &req.From, &req.To, &req.Limit)
+	})))
+	mux.HandleFunc("GET /v1/sessions/{id}", w.authed(restCall(w.admin.GetSession, func(r *http.Request, req *pb.GetSessionRequest) error {
+		req.Id = r.PathValue("id")
+		return nil
+	})))
+	mux.HandleFunc("PATCH /v1/sessions/{id}", w.authed(restCall(w.admin.UpdateSession, func(r *http.Request, req *pb.UpdateSessionRequest) error {
+		req.Id = r.PathValue("id")
+		return nil
+	})))
+
+	mux.HandleFunc("GET /v1/payments", w.authed(restCall(w.admin.ListPayments, func(r *http.Request, req *pb.ListPaymentsRequest) error {
+		q := r.URL.Query()
+		req.PcId, req.PageToken = q.Get("pc_id"), q.Get("page_token")
+		return queryRange(r, &req.From, &req.To, &req.Limit)
+	})))
+}
+
+// restCall turns an AdminService method into a handler. The request starts from the JSON
+// body, if any; fill then adds path and query values, which win over the body. Anything
+// but a GET must pass checkSameOrigin, since protojson would happily read a text/plain
+// body forged by another site.
+func restCall[Req proto.Message, Resp proto.Message](call func(context.Context, Req) (Resp, error), fill func(*http.Request, Req) error) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			if err := checkSameOrigin(r); err != nil {
+				writeError(rw, err)
+				return
+			}
+		}
+		var req Req
+		req = req.ProtoReflect().Type().New().Interface().(Req)
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+		if err != nil {
+			writeError(rw, status.Errorf(codes.InvalidArgument, "reading body: %v", err))
+			return
+		}
+		if len(body) > 0 {
+			if err := protojson.Unmarshal(body, req); err != nil {
+				writeError(rw, status.Errorf(codes.InvalidArgument, "invalid JSON body: %v", err))
+				return
+			}
+		}
+		if err := fill(r, req); err != nil {
+			writeError(rw, err)
+			return
+		}
+
+		resp, err := call(r.Context(), req)
+		if err != nil {
+			writeError(rw, err)
+			return
+		}
+		writeProto(rw, resp)
+	}
+}
+
+func queryBool(r *http.Request, key string) (bool, error) {
+	v := r.URL.Query().Get(key)
+	if v == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, status.Errorf(codes.InvalidArgument, "%s must be true or false", key)
+	}
+	return b, nil
+}
+
+// queryRange reads the from/to/limit parameters the list routes share.
+func queryRange(r *http.Request, from, to *int64, limit *int32) error {
+	q := r.URL.Query()
+	for key, dst := range map[string]*int64{"from": from, "to": to} {
+		if v := q.Get(key); v != "" {
+			n, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return status.Errorf(codes.InvalidArgument, "%s must be Unix seconds", key)
+			}
+			*dst = n
+		}
+	}
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 32)
+		if err != nil {
+			return status.Error(codes.InvalidArgument, "limit must be a number")
+		}
+		*limit = int32(n)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"hash/fnv"
+	"regexp"
+
+	pb "github.com/Mohammad-Mahdi82/NexusOps/pkg/monitor"
+	"github.com/Mohammad-Mahdi82/NexusOps/pkg/processrules"
+	"github.com/Mohammad-Mahdi82/NexusOps/server/models"
+	"google.golang.org/protobuf/proto"
+)
+
+// ProcessRuleConfig is one entry of the process_rules list in nexus_ops.json.
+type ProcessRuleConfig struct {
+	Pattern string `json:"pattern"`
+	Match   string `json:"match"`  // "name" (default), "path" or "regex"
+	Action  string `json:"action"` // "ignore" (default) or "track"
+}
+
+// defaultProcessRules ignores the shells, system tasks and launchers a Sentry also falls
+// back to before it hears from the server.
+func defaultProcessRules() []ProcessRuleConfig {
+	rules := make([]ProcessRuleConfig, 0, len(processrules.DefaultIgnored))
+	for _, n := range processrules.DefaultIgnored {
+		rules = append(rules, ProcessRuleConfig{Pattern: n, Match: "name", Action: "ignore"})
+	}
+	return rules
+}
+
+var (
+	ruleMatches = map[string]pb.ProcessRule_Match{
+		"":      pb.ProcessRule_NAME_GLOB,
+		"name":  pb.ProcessRule_NAME_GLOB,
+		"path":  pb.ProcessRule_PATH_GLOB,
+		"regex": pb.ProcessRule_REGEX,
+	}
+	ruleActions = map[string]pb.ProcessRule_Action{
+		"":       pb.ProcessRule_IGNORE,
+		"ignore": pb.ProcessRule_IGNORE,
+		"track":  pb.ProcessRule_TRACK,
+	}
+)
+
+// buildProcessRules validates the configured rules and stamps them with a content hash,
+// so clients only download them again when something actually changed. The configured
+// rules come first, so an operator's ignore rule beats a game classified by mistake.
+// Catalog games follow as TRACK rules so clients report them even while they're in the
+// background, matched on the full path for games the catalog keys by path. Entries
+// classified as not a game become IGNORE rules instead.
+func buildProcessRules(cfg []ProcessRuleConfig, games []*models.Game) (*pb.ProcessRules, error) {
+	set := &pb.ProcessRules{}
+	for i, r := range cfg {
+		match, ok := ruleMatches[r.Match]
+		if !ok {
+			return nil, fmt.Errorf("process rule %d: unknown match %q", i, r.Match)
+		}
+		action, ok := ruleActions[r.Action]
+		if !ok {
+			return nil, fmt.Errorf("process rule %d: unknown action %q", i, r.Action)
+		}
+		if match == pb.ProcessRule_REGEX {
+			if _, err := regexp.Compile(r.Pattern); err != nil {
+				return nil, fmt.Errorf("process rule %d: %w", i, err)
+			}
+		}
+		set.Rules = append(set.Rules, &pb.ProcessRule{Pattern: r.Pattern, Match: match, Action: action})
+	}
+	for _, g := range games {
+		match := pb.ProcessRule_NAME_GLOB
+		if isPathKey(g.Executable) {
+			match = pb.ProcessRule_PATH_GLOB
+		}
+		action := pb.ProcessRule_TRACK
+		if g.NotGame {
+			action = pb.ProcessRule_IGNORE
+		}
+		set.Rules = append(set.Rules, &pb.ProcessRule{Pattern: g.Executable, Match: match, Action: action})
+	}
+
+	version, err := contentVersion(set)
+	if err != nil {
+		return nil, err
+	}
+	set.Version = version
+	return set, nil
+}
+
+// contentVersion hashes a message pushed to clients, which report the version back.
+func contentVersion(m proto.Message) (int64, error) {
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(m)
+	if err != nil {
+		return 0, err
+	}
+	h := fnv.New64a()
+	h.Write(data)
+	// Keep it positive and never 0, which is what a client that has nothing yet reports
+	return int64(h.Sum64()>>1) | 1, nil
+}
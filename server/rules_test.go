@@ -0,0 +1,43 @@
+package main
+
+import (
+	"testing"
+
+	pb "github.com/Mohammad-Mahdi82/NexusOps/pkg/monitor"
+	"github.com/Mohammad-Mahdi82/NexusOps/server/models"
+)
+
+func TestBuildProcessRules(t *testing.T) {
+	cfg := []ProcessRuleConfig{{Pattern: "launcher.exe"}, {Pattern: `D:\Games\*`, Match: "path", Action: "track"}}
+	games := []*models.Game{{Executable: `c:\games\x\game.exe`}, {Executable: "launcher.exe"}, {Executable: "steam.exe", NotGame: true}}
+
+	set, err := buildProcessRules(cfg, games)
+	if err != nil {
+		t.Fatalf("buildProcessRules: %v", err)
+	}
+	want := []*pb.ProcessRule{
+		{Pattern: "launcher.exe", Match: pb.ProcessRule_NAME_GLOB, Action: pb.ProcessRule_IGNORE},
+		{Pattern: `D:\Games\*`, Match: pb.ProcessRule_PATH_GLOB, Action: pb.ProcessRule_TRACK},
+		{Pattern: `c:\games\x\game.exe`, Match: pb.ProcessRule_PATH_GLOB, Action: pb.ProcessRule_TRACK},
+		{Pattern: "launcher.exe", Match: pb.ProcessRule_NAME_GLOB, Action: pb.ProcessRule_TRACK},
+		{Pattern: "steam.exe", Match: pb.ProcessRule_NAME_GLOB, Action: pb.ProcessRule_IGNORE},
+	}
+	if len(set.Rules) != len(want) {
+		t.Fatalf("got %d rules, want %d: %v", len(set.Rules), len(want), set.Rules)
+	}
+	// Clients take the first match, so the configured ignore has to come before the catalog
+	for i, r := range set.Rules {
+		if r.Pattern != want[i].Pattern || r.Match != want[i].Match || r.Action != want[i].Action {
+			t.Errorf("rule %d = %v, want %v", i, r, want[i])
+		}
+	}
+
+	again, _ := buildProcessRules(cfg, games)
+	if set.Version == 0 || again.Version != set.Version {
+		t.Errorf("versions %d and %d, want the same non-zero version", set.Version, again.Version)
+	}
+
+	if _, err := buildProcessRules([]ProcessRuleConfig{{Pattern: "(", Match: "regex"}}, nil); err == nil {
+		t.Error("bad regex accepted")
+	}
+}
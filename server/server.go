@@ -2,61 +2,220 @@ package main
 
 import (
 	pb "github.com/Mohammad-Mahdi82/NexusOps/pkg/monitor"
-	"github.com/rivo/tview"
+	"github.com/Mohammad-Mahdi82/NexusOps/server/models"
 	"gorm.io/gorm"
+	"log/slog"
+	"strings"
 	"sync"
+	"time"
 )
 
-const HourlyRate = 50000
+const (
+	HourlyRate       = 50000
+	DefaultRateClass = "standard"
+)
+
+// Billing policies: keep billing a game while it runs in the background, or only while focused.
+const (
+	BillRunning = "running"
+	BillFocused = "focused"
+)
 
 type server struct {
 	pb.UnimplementedNexusServiceServer
-	db               *gorm.DB
-	mu               sync.Mutex
-	pcStates         map[string]string
-	activeSessionIDs map[string]string
-	killSignals      map[string]bool
-
-	app      *tview.Application
-	mainFlex *tview.Flex
-	pcTables []*tview.Table
+	db           *gorm.DB
+	mu           sync.Mutex
+	pcStates     map[string]string
+	liveSessions map[string]*liveSession
+	pcGames      map[string][]*pb.GameProcess
+	pcNotices    map[string]string
+	idleStage    map[string]int
+	pcLocked     map[string]bool
+	outbox       map[string]chan outgoing
+	stations     map[string]*models.Station
+	killSignals  map[string]bool
+	catalog      map[string]*models.Game
+
+	cfg          Config
+	loc          *Locale
+	printer      Printer
+	processRules *pb.ProcessRules
+	settings     *pb.ClientSettings
+
+	watchMu     sync.Mutex
+	subscribers map[chan *pb.Event]bool // Attached consoles and dashboards
+	logs        *logHub
 }
 
+func newServer(cfg Config, db *gorm.DB, printer Printer, settings *pb.ClientSettings, logs *logHub) *server {
+	return &server{
+		db:           db,
+		pcStates:     make(map[string]string),
+		liveSessions: make(map[string]*liveSession),
+		pcGames:      make(map[string][]*pb.GameProcess),
+		pcNotices:    make(map[string]string),
+		idleStage:    make(map[string]int),
+		pcLocked:     make(map[string]bool),
+		outbox:       make(map[string]chan outgoing),
+		stations:     make(map[string]*models.Station),
+		killSignals:  make(map[string]bool),
+		catalog:      make(map[string]*models.Game),
+		cfg:          cfg,
+		loc:          newLocale(cfg.Locale),
+		printer:      printer,
+		settings:     settings,
+		subscribers:  make(map[chan *pb.Event]bool),
+		logs:         logs,
+	}
+}
+
+// StreamSession receives heartbeats on this goroutine while a second one writes
+// commands, so the server can reach a PC the moment the operator acts.
 func (s *server) StreamSession(stream pb.NexusService_StreamSessionServer) error {
+	out := make(chan outgoing, outboxSize)
+	ctx := stream.Context()
+	go func() {
+		for {
+			select {
+			case cmd := <-out:
+				if err := stream.Send(cmd.resp); err != nil {
+					return // Recv fails too once the stream is gone
+				}
+				commandLatency.Observe(time.Since(cmd.queued).Seconds())
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
 	var currentPC string
 	for {
 		req, err := stream.Recv()
 		if err != nil {
 			s.mu.Lock()
-			if currentPC != "" {
+			// A PC that already reconnected on a new stream owns its state now
+			if currentPC != "" && s.outbox[currentPC] == out {
 				s.finalizeSession(currentPC)
 				delete(s.pcStates, currentPC)
+				delete(s.pcGames, currentPC)
+				delete(s.pcNotices, currentPC)
+				delete(s.idleStage, currentPC)
+				delete(s.pcLocked, currentPC)
+				delete(s.outbox, currentPC)
+				s.touchStation(currentPC)
+				s.emit(pb.Event_STATION_DISCONNECTED, currentPC, nil)
 			}
 			s.mu.Unlock()
-			s.refreshUI()
 			return err
 		}
 
+		heartbeatsTotal.Inc()
+
 		s.mu.Lock()
+		connecting := currentPC == ""
+		if connecting {
+			if !s.stationFor(req.PcId).LastSeen.IsZero() {
+				streamReconnects.Inc()
+			}
+			s.touchStation(req.PcId)
+		}
 		currentPC = req.PcId
-		newGame := req.CurrentGame
+		s.outbox[currentPC] = out
 		oldGame := s.pcStates[currentPC]
+		newGame := s.billedGame(req, oldGame)
+		oldNotice, wasLocked := s.pcNotices[currentPC], s.pcLocked[currentPC]
+
+		notice := s.applyIdlePolicy(currentPC, time.Duration(req.IdleSeconds)*time.Second)
+		if s.idleStage[currentPC] == idleLocked {
+			newGame = "Idle" // Abandoned: end the session even if the game refuses to close
+		}
+		if locked, _ := s.applyLock(currentPC); locked {
+			newGame = "Idle" // Nobody should be playing on a locked PC
+		}
+		s.pcGames[currentPC] = req.Games
+		for _, result := range req.CloseResults {
+			s.recordCloseResult(currentPC, result)
+		}
 
 		// Logic delegation to Service methods
 		s.handleGameTransition(currentPC, oldGame, newGame)
-
 		s.pcStates[currentPC] = newGame
-		shouldKill := s.killSignals[currentPC]
-		if shouldKill {
-			s.killSignals[currentPC] = false
+		switch {
+		case connecting:
+			s.emit(pb.Event_STATION_CONNECTED, currentPC, nil)
+		default:
+			s.emitGame(currentPC, oldGame, newGame)
+		}
+		if !connecting && (s.pcNotices[currentPC] != oldNotice || s.pcLocked[currentPC] != wasLocked) {
+			s.emit(pb.Event_STATION_UPDATED, currentPC, nil)
+		}
+
+		resp := s.commandFor(currentPC)
+		resp.Notice = notice
+		// Keep pushing the rules until the client reports it applied them
+		if req.RulesVersion != s.processRules.Version {
+			resp.ProcessRules = s.processRules
+		}
+		if req.SettingsVersion != s.settings.Version {
+			resp.Settings = s.settings
 		}
 		s.mu.Unlock()
 
-		s.refreshUI()
+		select {
+		case out <- outgoing{resp, time.Now()}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
 
-		err = stream.Send(&pb.CommandResponse{CloseActiveGame: shouldKill})
-		if err != nil {
-			return err
+// billedGame decides which game a heartbeat should be billed as. Under the "running"
+// policy an alt-tab doesn't end the session: the game keeps billing while its process lives.
+func (s *server) billedGame(req *pb.Heartbeat, oldGame string) string {
+	// Clients that predate process reporting only tell us about focus
+	if s.cfg.BillingPolicy == BillFocused || len(req.Games) == 0 {
+		return req.CurrentGame
+	}
+
+	// Window titles and process snapshots don't always agree on case
+	running := make(map[string]bool, len(req.Games))
+	for _, g := range req.Games {
+		running[strings.ToLower(g.Name)] = true
+	}
+	switch {
+	case running[strings.ToLower(oldGame)]:
+		return oldGame
+	case running[strings.ToLower(req.CurrentGame)]:
+		return req.CurrentGame
+	default:
+		// The client sorts by start time, so this is the longest-running game
+		return req.Games[0].Name
+	}
+}
+
+// gamesToClose picks the processes a close command should target: the billed game and
+// anything the catalog knows is a game, but never a browser that merely had focus.
+// Callers must hold s.mu.
+func (s *server) gamesToClose(pcID string) []*pb.GameProcess {
+	billed := strings.ToLower(s.pcStates[pcID])
+
+	var targets []*pb.GameProcess
+	for _, g := range s.pcGames[pcID] {
+		name := strings.ToLower(g.Name)
+		if entry, known := s.lookupGame(g.Name, g.Path); name == billed || (known && entry.Classified) {
+			targets = append(targets, g)
 		}
 	}
+	return targets
+}
+
+// recordCloseResult surfaces what the client did with a close command. Callers must hold s.mu.
+func (s *server) recordCloseResult(pcID string, r *pb.CloseResult) {
+	key := "close." + strings.ToLower(r.Outcome.String())
+	s.pcNotices[pcID] = s.loc.T(key, r.Name)
+	if r.Error != "" {
+		slog.Warn("Closing game failed", "pc", pcID, "game", r.Name, "pid", r.Pid, "outcome", r.Outcome, "err", r.Error)
+	} else {
+		slog.Info("Closed game", "pc", pcID, "game", r.Name, "pid", r.Pid, "outcome", r.Outcome)
+	}
 }
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	pb "github.com/Mohammad-Mahdi82/NexusOps/pkg/monitor"
+	"google.golang.org/grpc"
+)
+
+// fakeStream is one Sentry connection: the test feeds heartbeats in and reads commands out.
+type fakeStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	cancel context.CancelFunc
+	recv   chan *pb.Heartbeat
+	sent   chan *pb.CommandResponse
+	done   chan error
+}
+
+func openStream(s *server) *fakeStream {
+	ctx, cancel := context.WithCancel(context.Background())
+	f := &fakeStream{ctx: ctx, cancel: cancel, recv: make(chan *pb.Heartbeat), sent: make(chan *pb.CommandResponse, 8), done: make(chan error, 1)}
+	go func() { f.done <- s.StreamSession(f) }()
+	return f
+}
+
+func (f *fakeStream) Context() context.Context { return f.ctx }
+
+func (f *fakeStream) Recv() (*pb.Heartbeat, error) {
+	select {
+	case hb := <-f.recv:
+		return hb, nil
+	case <-f.ctx.Done():
+		return nil, errors.New("connection lost")
+	}
+}
+
+func (f *fakeStream) Send(resp *pb.CommandResponse) error {
+	f.sent <- resp
+	return nil
+}
+
+// heartbeat sends one heartbeat and waits for the server's answer.
+func (f *fakeStream) heartbeat(t *testing.T, hb *pb.Heartbeat) {
+	t.Helper()
+	f.recv <- hb
+	select {
+	case <-f.sent:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no answer to heartbeat")
+	}
+}
+
+// drop cuts the connection and waits for StreamSession to clean up.
+func (f *fakeStream) drop(t *testing.T) {
+	t.Helper()
+	f.cancel()
+	select {
+	case <-f.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("StreamSession didn't return")
+	}
+}
+
+func TestReconnectKeepsNewStream(t *testing.T) {
+	s := newTestServer(t)
+	s.processRules = &pb.ProcessRules{}
+	s.mu.Lock()
+	events := s.subscribe()
+	s.mu.Unlock()
+	defer s.unsubscribe(events)
+
+	playing := &pb.Heartbeat{PcId: "PC-01", CurrentGame: "cs2.exe"}
+	old := openStream(s)
+	old.heartbeat(t, playing)
+	// The PC reconnects before the server notices the old stream is dead
+	current := openStream(s)
+	current.heartbeat(t, playing)
+	old.drop(t)
+
+	s.mu.Lock()
+	game, ls, owned := s.pcStates["PC-01"], s.liveSessions["PC-01"], s.outbox["PC-01"] != nil
+	s.mu.Unlock()
+	if game != "cs2.exe" || ls == nil || !ls.sess.IsActive || !owned {
+		t.Errorf("after the old stream dropped: game %q, session %v, outbox %v", game, ls, owned)
+	}
+	for len(events) > 0 {
+		if ev := <-events; ev.Type == pb.Event_STATION_DISCONNECTED {
+			t.Errorf("old stream reported %s disconnected", ev.PcId)
+		}
+	}
+
+	current.drop(t)
+	s.mu.Lock()
+	_, stillThere := s.pcStates["PC-01"]
+	s.mu.Unlock()
+	if stillThere {
+		t.Error("state left behind after the last stream dropped")
+	}
+	disconnected := false
+	for len(events) > 0 {
+		disconnected = disconnected || (<-events).Type == pb.Event_STATION_DISCONNECTED
+	}
+	if !disconnected {
+		t.Error("no STATION_DISCONNECTED after the last stream dropped")
+	}
+}
@@ -1,8 +1,14 @@
 package main
 
 import (
+	"errors"
+	"fmt"
+
+	pb "github.com/Mohammad-Mahdi82/NexusOps/pkg/monitor"
 	"github.com/Mohammad-Mahdi82/NexusOps/server/models"
 	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"log/slog"
 	"time"
 )
 
@@ -17,7 +23,7 @@ func (s *server) handleGameTransition(pcID, oldGame, newGame string) {
 	if (oldGame == "" || oldGame == "Idle") && newGame != "Idle" {
 		s.startNewSession(pcID, newGame)
 	} else if oldGame == newGame && newGame != "Idle" {
-		if s.activeSessionIDs[pcID] == "" {
+		if s.liveSessions[pcID] == nil {
 			s.startNewSession(pcID, newGame)
 		} else {
 			s.updateLiveSession(pcID)
@@ -31,44 +37,174 @@ func (s *server) handleGameTransition(pcID, oldGame, newGame string) {
 }
 
 func (s *server) startNewSession(pcID string, game string) {
+	path := s.gamePath(pcID, game)
+	entry := s.gameFor(game, path)
 	session := models.Session{
-		PcID: pcID, GameName: game, StartTime: time.Now(),
+		PcID: pcID, GameName: game, GamePath: path, GameTitle: entry.Title, RateClass: entry.RateClass, StartTime: time.Now(),
 		EndTime: time.Now(), IsActive: true,
 		Fee: decimal.NewFromInt(0), Paid: false,
 	}
 	s.db.Create(&session)
-	s.activeSessionIDs[pcID] = session.ID
+	s.liveSessions[pcID] = &liveSession{sess: session}
 }
 
+// updateLiveSession re-prices the running session in memory. Time spent idle past the
+// pause threshold is set aside and not billed.
 func (s *server) updateLiveSession(pcID string) {
-	sessionID := s.activeSessionIDs[pcID]
-	var sess models.Session
-	if err := s.db.First(&sess, "id = ?", sessionID).Error; err != nil {
+	ls := s.liveSessions[pcID]
+	if ls == nil {
 		return
 	}
-	duration := time.Since(sess.StartTime)
-	fee := decimal.NewFromFloat(duration.Hours()).Mul(decimal.NewFromInt(HourlyRate)).Round(0)
-	s.db.Model(&sess).Updates(map[string]interface{}{
-		"end_time": time.Now(), "duration_minutes": int(duration.Minutes()), "fee": fee,
-	})
+	sess := &ls.sess
+	now := time.Now()
+	paused := time.Duration(sess.PausedSeconds) * time.Second
+	if s.idleStage[pcID] >= idlePaused {
+		paused += now.Sub(sess.EndTime)
+	}
+	duration := now.Sub(sess.StartTime) - paused
+	sess.EndTime = now
+	sess.DurationMinutes = int(duration.Minutes())
+	sess.Fee = decimal.NewFromFloat(duration.Hours()).Mul(s.hourlyRate(sess.RateClass)).Round(0)
+	sess.PausedSeconds = int(paused.Round(time.Second).Seconds())
+	ls.dirty = true
 }
 
+// finalizeSession writes the session out for the last time, whatever the flush schedule.
 func (s *server) finalizeSession(pcID string) {
-	sessionID := s.activeSessionIDs[pcID]
-	if sessionID == "" {
+	ls := s.liveSessions[pcID]
+	if ls == nil {
 		return
 	}
-	s.db.Model(&models.Session{}).Where("id = ?", sessionID).Update("is_active", false)
-	delete(s.activeSessionIDs, pcID)
+	ls.sess.IsActive = false
+	if err := saveLiveSession(s.db, &ls.sess); err != nil {
+		slog.Error("Could not save session", "pc", pcID, "err", err)
+	}
+	delete(s.liveSessions, pcID)
+	s.emit(pb.Event_SESSION_FINALIZED, pcID, func(ev *pb.Event) { ev.Session = toPBSession(ls.sess) })
 }
 
-func (s *server) MarkAsPaid(pcID string) {
+// MarkAsPaid settles every unpaid session of a PC as one Payment and prints its receipt.
+// The discount comes off the subtotal before tax. The payment is nil when the PC had
+// nothing to pay.
+func (s *server) MarkAsPaid(pcID, method, operator string, discount decimal.Decimal) (*models.Payment, error) {
 	s.mu.Lock()
-	defer s.mu.Unlock()
+	s.flushSessions() // The receipt has to include the fee accrued since the last flush
+	payment, receipt, err := s.settle(pcID, method, operator, discount)
+	if err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	delete(s.liveSessions, pcID)
+	s.killSignals[pcID] = true
+	if err := s.closeStation(pcID); err != nil {
+		slog.Error("Could not lock station", "pc", pcID, "err", err)
+	}
+	if payment != nil {
+		revenueTotal.WithLabelValues(method).Add(payment.Total.InexactFloat64())
+		paymentsTotal.WithLabelValues(method).Inc()
+		s.emit(pb.Event_PAYMENT_RECORDED, pcID, func(ev *pb.Event) {
+			ev.Payment = toPBPayment(*payment)
+			ev.TodayRevenue = s.revenueSince(startOfDay(time.Now())).String()
+		})
+	}
+	s.mu.Unlock()
+	s.sendCommand(pcID)
+
+	if receipt != nil && s.printer != nil {
+		if err := s.printer.Print(receipt); err != nil {
+			slog.Error("Receipt printing failed", "pc", pcID, "err", err)
+		}
+	}
+	return payment, nil
+}
+
+func (s *server) settle(pcID, method, operator string, discount decimal.Decimal) (*models.Payment, *Receipt, error) {
+	var sessions []models.Session
+	s.db.Where("pc_id = ? AND paid = ?", pcID, false).Order("start_time asc").Find(&sessions)
+	if len(sessions) == 0 {
+		return nil, nil, nil
+	}
+
+	subtotal := decimal.Zero
+	for _, sess := range sessions {
+		subtotal = subtotal.Add(sess.Fee)
+	}
+	if discount.GreaterThan(subtotal) {
+		return nil, nil, fmt.Errorf("%w: %s off a subtotal of %s", errDiscountTooLarge, discount, subtotal)
+	}
+	tax := subtotal.Sub(discount).Mul(decimal.NewFromFloat(s.cfg.Receipt.TaxPercent)).Div(decimal.NewFromInt(100)).Round(0)
+
+	payment := models.Payment{
+		PcID: pcID, Subtotal: subtotal, Discount: discount, Tax: tax,
+		Total:  subtotal.Sub(discount).Add(tax),
+		Method: method, Operator: operator,
+	}
+
 	now := time.Now()
-	s.db.Model(&models.Session{}).Where("pc_id = ? AND paid = ?", pcID, false).Updates(map[string]interface{}{
-		"paid": true, "payment_time": &now, "is_active": false,
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&payment).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Session{}).Where("pc_id = ? AND paid = ?", pcID, false).Updates(map[string]interface{}{
+			"paid": true, "payment_time": &now, "is_active": false, "payment_id": payment.ID,
+		}).Error
 	})
-	delete(s.activeSessionIDs, pcID)
-	s.killSignals[pcID] = true
+	if err != nil {
+		return nil, nil, err
+	}
+	return &payment, buildReceipt(s.cfg.Receipt, s.loc, payment, sessions), nil
+}
+
+var (
+	errSessionRunning   = errors.New("session is still running")
+	errSessionPaid      = errors.New("session is already paid")
+	errDiscountTooLarge = errors.New("discount is more than the subtotal")
+)
+
+// SessionEdit holds the corrections an operator makes to a session. Nil fields are kept.
+type SessionEdit struct {
+	RateClass       *string
+	DurationMinutes *int
+	Fee             *decimal.Decimal // Wins over the price the other fields imply
+}
+
+// editSession corrects an ended, unpaid session. Paid ones are part of a receipt and
+// running ones are re-priced every few seconds, so both are refused.
+func (s *server) editSession(id string, edit SessionEdit) (*models.Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, ls := range s.liveSessions {
+		if ls.sess.ID == id {
+			return nil, errSessionRunning
+		}
+	}
+	var sess models.Session
+	if err := s.db.First(&sess, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	if sess.Paid {
+		return nil, errSessionPaid
+	}
+
+	reprice := false
+	if edit.RateClass != nil {
+		sess.RateClass = *edit.RateClass
+		reprice = true
+	}
+	if edit.DurationMinutes != nil {
+		sess.DurationMinutes = *edit.DurationMinutes
+		reprice = true
+	}
+	switch {
+	case edit.Fee != nil:
+		sess.Fee = *edit.Fee
+	case reprice:
+		hours := decimal.NewFromInt(int64(sess.DurationMinutes)).Div(decimal.NewFromInt(60))
+		sess.Fee = hours.Mul(s.hourlyRate(sess.RateClass)).Round(0)
+	}
+	if err := s.db.Save(&sess).Error; err != nil {
+		return nil, err
+	}
+	return &sess, nil
 }
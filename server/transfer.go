@@ -0,0 +1,314 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/Mohammad-Mahdi82/NexusOps/server/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// transferTables are what export and import move, in the order they are written.
+var transferTables = []string{"stations", "sessions", "payments"}
+
+// transferFlags are the flags export and import share.
+type transferFlags struct {
+	dir, format, tables, db *string
+}
+
+func newTransferFlags(fs *flag.FlagSet) transferFlags {
+	return transferFlags{
+		dir:    fs.String("dir", "export", "Directory with one file per table"),
+		format: fs.String("format", "csv", "File format, csv or json"),
+		tables: fs.String("tables", strings.Join(transferTables, ","), "Comma-separated tables"),
+		db:     fs.String("db", "", "Database file (default db_path from nexus_ops.json)"),
+	}
+}
+
+// open checks the flags and opens the database, creating its tables if need be.
+func (f transferFlags) open() (*gorm.DB, []string, error) {
+	if *f.format != "csv" && *f.format != "json" {
+		return nil, nil, fmt.Errorf("-format must be csv or json, not %q", *f.format)
+	}
+	var tables []string
+	for _, t := range strings.Split(*f.tables, ",") {
+		t = strings.TrimSpace(t)
+		switch {
+		case t == "customers":
+			return nil, nil, errors.New("there are no customers to move: NexusOps bills PCs, not customer accounts")
+		case !slices.Contains(transferTables, t):
+			return nil, nil, fmt.Errorf("unknown table %q, want %s", t, strings.Join(transferTables, ", "))
+		}
+		tables = append(tables, t)
+	}
+
+	path := *f.db
+	if path == "" {
+		cfg, err := LoadConfig()
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid config: %w", err)
+		}
+		path = resolvePath(cfg.DBPath)
+	}
+	db, err := InitDB(path)
+	return db, tables, err
+}
+
+func (f transferFlags) file(table string) string {
+	return filepath.Join(*f.dir, table+"."+*f.format)
+}
+
+// runExport implements `nexus-server export`. Sessions are picked by when they started
+// and payments by when they were taken; stations are always exported whole.
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	f := newTransferFlags(fs)
+	fromFlag := fs.String("from", "", "First day, YYYY-MM-DD")
+	toFlag := fs.String("to", "", "Last day, YYYY-MM-DD, included")
+	fs.Parse(args)
+
+	from, to, err := dayRange(*fromFlag, *toFlag)
+	if err != nil {
+		return err
+	}
+	db, tables, err := f.open()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(*f.dir, 0o755); err != nil {
+		return err
+	}
+	between := func(column string) *gorm.DB {
+		q := db.Order(column + " asc, id asc")
+		if !from.IsZero() {
+			q = q.Where(column+" >= ?", from)
+		}
+		if !to.IsZero() {
+			q = q.Where(column+" < ?", to)
+		}
+		return q
+	}
+
+	for _, table := range tables {
+		var n int
+		switch table {
+		case "stations":
+			n, err = exportTable[models.Station](db.Order("id asc"), f.file(table), *f.format)
+		case "sessions":
+			n, err = exportTable[models.Session](between("start_time"), f.file(table), *f.format)
+		case "payments":
+			n, err = exportTable[models.Payment](between("created_at"), f.file(table), *f.format)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", table, err)
+		}
+		fmt.Printf("%s: %d exported to %s\n", table, n, f.file(table))
+	}
+	return nil
+}
+
+// runImport implements `nexus-server import`. Rows whose ID is already in the database
+// are left as they are, so importing the same files twice changes nothing.
+func runImport(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	f := newTransferFlags(fs)
+	fs.Parse(args)
+
+	db, tables, err := f.open()
+	if err != nil {
+		return err
+	}
+	for _, table := range tables {
+		path := f.file(table)
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			fmt.Printf("%s: no %s, skipped\n", table, path)
+			continue
+		}
+
+		var read, added int
+		switch table {
+		case "stations":
+			read, added, err = importTable(db, path, *f.format, func(st *models.Station) string { return st.ID })
+		case "sessions":
+			read, added, err = importTable(db, path, *f.format, importedSession)
+		case "payments":
+			read, added, err = importTable(db, path, *f.format, func(p *models.Payment) string { return p.ID })
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", table, err)
+		}
+		fmt.Printf("%s: %d read from %s, %d added, %d already there\n", table, read, path, added, read-added)
+	}
+	return nil
+}
+
+// importedSession stores a session as ended: nothing is billing it here, and the server
+// that exported it will close it.
+func importedSession(sess *models.Session) string {
+	sess.IsActive = false
+	return sess.ID
+}
+
+// exportTable writes every row q finds to path.
+func exportTable[T any](q *gorm.DB, path, format string) (int, error) {
+	var rows []T
+	if err := q.Find(&rows).Error; err != nil {
+		return 0, err
+	}
+	out, err := os.Create(path)
+	if err != nil {
+		return 0, err
+	}
+	defer out.Close()
+
+	if format == "json" {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(rows)
+	} else {
+		err = writeCSV(out, rows)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return len(rows), out.Close()
+}
+
+// importTable adds the rows in path that aren't in the database yet, all or none.
+// prepare fixes up a row before it is stored and returns its ID.
+func importTable[T any](db *gorm.DB, path, format string, prepare func(*T) string) (read, added int, err error) {
+	in, err := os.Open(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer in.Close()
+
+	var rows []T
+	if format == "json" {
+		err = json.NewDecoder(in).Decode(&rows)
+	} else {
+		rows, err = readCSV[T](in)
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	for i := range rows {
+		if prepare(&rows[i]) == "" {
+			return 0, 0, fmt.Errorf("row %d has no id", i+1)
+		}
+	}
+	if len(rows) == 0 {
+		return 0, 0, nil
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(&rows, 200)
+		added = int(res.RowsAffected)
+		return res.Error
+	})
+	return len(rows), added, err
+}
+
+// csvColumns picks a model's CSV columns: the fields with a json tag, named by it. Cells
+// hold the JSON value without its quotes: RFC 3339 times, decimals as written, empty for nil.
+func csvColumns(t reflect.Type) (names []string, fields []int) {
+	for i := range t.NumField() {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			names = append(names, name)
+			fields = append(fields, i)
+		}
+	}
+	return names, fields
+}
+
+func writeCSV[T any](w io.Writer, rows []T) error {
+	names, fields := csvColumns(reflect.TypeFor[T]())
+	cw := csv.NewWriter(w)
+	cw.Write(names)
+	record := make([]string, len(fields))
+	for _, row := range rows {
+		v := reflect.ValueOf(row)
+		for i, f := range fields {
+			field := v.Field(f)
+			if field.Kind() == reflect.String {
+				record[i] = field.String()
+				continue
+			}
+			data, err := json.Marshal(field.Interface())
+			if err != nil {
+				return err
+			}
+			record[i] = strings.Trim(string(data), `"`)
+			if record[i] == "null" {
+				record[i] = ""
+			}
+		}
+		cw.Write(record)
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func readCSV[T any](r io.Reader) ([]T, error) {
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("no header row: %w", err)
+	}
+	names, fields := csvColumns(reflect.TypeFor[T]())
+	column := make([]int, len(header)) // Field of each column, -1 for ones we don't know
+	for i, h := range header {
+		column[i] = -1
+		if j := slices.Index(names, strings.TrimSpace(h)); j >= 0 {
+			column[i] = fields[j]
+		}
+	}
+
+	var rows []T
+	for line := 2; ; line++ {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		var row T
+		v := reflect.ValueOf(&row).Elem()
+		for i, value := range record {
+			if column[i] < 0 || value == "" {
+				continue
+			}
+			if err := setCSVField(v.Field(column[i]), value); err != nil {
+				return nil, fmt.Errorf("line %d, %s: %w", line, header[i], err)
+			}
+		}
+		rows = append(rows, row)
+	}
+}
+
+// setCSVField parses a cell the way JSON would: as it is for numbers and booleans,
+// quoted for times and decimals.
+func setCSVField(field reflect.Value, value string) error {
+	if field.Kind() == reflect.String {
+		field.SetString(value)
+		return nil
+	}
+	target := field.Addr().Interface()
+	if json.Unmarshal([]byte(value), target) == nil {
+		return nil
+	}
+	quoted, _ := json.Marshal(value)
+	return json.Unmarshal(quoted, target)
+}
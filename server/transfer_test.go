@@ -0,0 +1,145 @@
+package main
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Mohammad-Mahdi82/NexusOps/server/models"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+func TestTransferRoundTrip(t *testing.T) {
+	src := newTestServer(t)
+	ptr := func(v int) *int { return &v }
+	start := time.Date(2026, 10, 18, 14, 0, 0, 0, time.Local)
+	paidAt := start.Add(2 * time.Hour)
+	prepaid := start.Add(3 * time.Hour)
+
+	mustCreate(t, src, &models.Station{ID: "PC-01", IdleWarnMinutes: ptr(5), IdleLockMinutes: ptr(0),
+		Open: true, PrepaidUntil: &prepaid, LastSeen: start})
+	mustCreate(t, src, &models.Station{ID: "PC-02", LastSeen: start})
+	mustCreate(t, src, &models.Session{ID: "ended", PcID: "PC-01", GameName: "game.exe", GamePath: `C:\Games\Racer\game.exe`,
+		GameTitle: "Racer, Deluxe", RateClass: "vip", StartTime: start, EndTime: start.Add(time.Hour), DurationMinutes: 55,
+		PausedSeconds: 300, Fee: decimal.RequireFromString("12345.67"), Paid: true, PaymentTime: &paidAt, PaymentID: "pay"})
+	mustCreate(t, src, &models.Session{ID: "running", PcID: "PC-02", GameName: "cs2.exe", StartTime: start, IsActive: true})
+	mustCreate(t, src, &models.Payment{ID: "pay", PcID: "PC-01", Subtotal: decimal.RequireFromString("12345.67"),
+		Discount: decimal.RequireFromString("345.67"), Tax: decimal.RequireFromString("1080"), Total: decimal.RequireFromString("13080"),
+		Method: "card", Operator: "sara", CreatedAt: paidAt})
+
+	for _, format := range []string{"csv", "json"} {
+		t.Run(format, func(t *testing.T) {
+			dst := newTestServer(t)
+			dir := t.TempDir()
+			transfer := func(table string, export func(path string) (int, error), imp func(path string) (int, int, error)) {
+				t.Helper()
+				path := filepath.Join(dir, table+"."+format)
+				exported, err := export(path)
+				if err != nil {
+					t.Fatalf("export %s: %v", table, err)
+				}
+				for i, wantAdded := range []int{exported, 0} { // The second import finds every row already there
+					read, added, err := imp(path)
+					if err != nil || read != exported || added != wantAdded {
+						t.Fatalf("import %s #%d: read %d, added %d, err %v; want %d read, %d added", table, i+1, read, added, err, exported, wantAdded)
+					}
+				}
+			}
+			transfer("stations",
+				func(path string) (int, error) {
+					return exportTable[models.Station](src.db.Order("id asc"), path, format)
+				},
+				func(path string) (int, int, error) {
+					return importTable(dst.db, path, format, func(st *models.Station) string { return st.ID })
+				})
+			transfer("sessions",
+				func(path string) (int, error) {
+					return exportTable[models.Session](src.db.Order("id asc"), path, format)
+				},
+				func(path string) (int, int, error) { return importTable(dst.db, path, format, importedSession) })
+			transfer("payments",
+				func(path string) (int, error) {
+					return exportTable[models.Payment](src.db.Order("id asc"), path, format)
+				},
+				func(path string) (int, int, error) {
+					return importTable(dst.db, path, format, func(p *models.Payment) string { return p.ID })
+				})
+
+			compareStations(t, src.db, dst.db)
+			compareSessions(t, src.db, dst.db)
+			comparePayments(t, src.db, dst.db)
+		})
+	}
+}
+
+func compareStations(t *testing.T, src, dst *gorm.DB) {
+	t.Helper()
+	var want, got []models.Station
+	src.Order("id asc").Find(&want)
+	dst.Order("id asc").Find(&got)
+	if len(got) != len(want) {
+		t.Fatalf("%d stations, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		g := got[i]
+		if g.ID != w.ID || !equalIntPtr(g.IdleWarnMinutes, w.IdleWarnMinutes) || !equalIntPtr(g.IdlePauseMinutes, w.IdlePauseMinutes) ||
+			!equalIntPtr(g.IdleLockMinutes, w.IdleLockMinutes) || g.Open != w.Open || !equalTimePtr(g.PrepaidUntil, w.PrepaidUntil) ||
+			!g.LastSeen.Equal(w.LastSeen) || !g.CreatedAt.Equal(w.CreatedAt) {
+			t.Errorf("station imported as %+v, want %+v", g, w)
+		}
+	}
+}
+
+func compareSessions(t *testing.T, src, dst *gorm.DB) {
+	t.Helper()
+	var want, got []models.Session
+	src.Order("id asc").Find(&want)
+	dst.Order("id asc").Find(&got)
+	if len(got) != len(want) {
+		t.Fatalf("%d sessions, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		g := got[i]
+		if g.ID != w.ID || g.PcID != w.PcID || g.GameName != w.GameName || g.GamePath != w.GamePath || g.GameTitle != w.GameTitle ||
+			g.RateClass != w.RateClass || !g.StartTime.Equal(w.StartTime) || !g.EndTime.Equal(w.EndTime) ||
+			g.DurationMinutes != w.DurationMinutes || g.PausedSeconds != w.PausedSeconds || !g.Fee.Equal(w.Fee) ||
+			g.Paid != w.Paid || !equalTimePtr(g.PaymentTime, w.PaymentTime) || g.PaymentID != w.PaymentID {
+			t.Errorf("session imported as %+v, want %+v", g, w)
+		}
+		if g.IsActive {
+			t.Errorf("session %s imported as running", g.ID)
+		}
+	}
+	// The running session has to have gone through as one, zero end time and all
+	if i := slices.IndexFunc(want, func(s models.Session) bool { return s.IsActive }); i < 0 || !want[i].EndTime.IsZero() {
+		t.Errorf("no running session with a zero end time in %+v", want)
+	}
+}
+
+func comparePayments(t *testing.T, src, dst *gorm.DB) {
+	t.Helper()
+	var want, got []models.Payment
+	src.Order("id asc").Find(&want)
+	dst.Order("id asc").Find(&got)
+	if len(got) != len(want) {
+		t.Fatalf("%d payments, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		g := got[i]
+		if g.ID != w.ID || g.PcID != w.PcID || !g.Subtotal.Equal(w.Subtotal) || !g.Discount.Equal(w.Discount) ||
+			!g.Tax.Equal(w.Tax) || !g.Total.Equal(w.Total) || g.Method != w.Method || g.Operator != w.Operator ||
+			!g.CreatedAt.Equal(w.CreatedAt) {
+			t.Errorf("payment imported as %+v, want %+v", g, w)
+		}
+	}
+}
+
+func equalIntPtr(a, b *int) bool {
+	return a == nil && b == nil || a != nil && b != nil && *a == *b
+}
+
+func equalTimePtr(a, b *time.Time) bool {
+	return a == nil && b == nil || a != nil && b != nil && a.Equal(*b)
+}
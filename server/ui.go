@@ -1,80 +1,355 @@
 package main
 
 import (
+	"context"
 	"fmt"
-	"sort"
+	"strconv"
+	"time"
 
-	"github.com/Mohammad-Mahdi82/NexusOps/server/models"
+	pb "github.com/Mohammad-Mahdi82/NexusOps/pkg/monitor"
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 	"github.com/shopspring/decimal"
 )
 
-func (s *server) refreshUI() {
-	s.app.QueueUpdateDraw(func() {
-		s.mu.Lock()
-		defer s.mu.Unlock()
+func (c *console) refreshUI() {
+	c.app.QueueUpdateDraw(func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
 
-		s.mainFlex.Clear()
-		s.pcTables = nil
+		c.mainFlex.Clear()
+		c.pcTables = nil
 
-		var connectedPCs []string
-		for id := range s.pcStates {
-			connectedPCs = append(connectedPCs, id)
+		footerText := c.loc.T("footer")
+		if c.dash != nil {
+			footerText += c.loc.T("footer.today", c.loc.Digits(money(c.dash.TodayRevenue)))
 		}
-		sort.Strings(connectedPCs)
+		if c.dash != nil && c.dash.PendingGames > 0 {
+			footerText += c.loc.T("footer.pending", c.dash.PendingGames)
+		}
+		if c.status != "" {
+			footerText += " | " + c.status
+		}
+		c.footer.SetText(footerText)
 
-		if len(connectedPCs) == 0 {
-			emptyMsg := tview.NewTextView().SetText("\n\nNo PCs Connected.").SetTextAlign(tview.AlignCenter)
-			s.mainFlex.AddItem(emptyMsg, 0, 1, false)
+		if !c.connected {
+			msg := tview.NewTextView().SetText("\n\n" + c.loc.T("console.disconnected", c.addr)).SetTextAlign(tview.AlignCenter)
+			c.mainFlex.AddItem(msg, 0, 1, false)
+			return
+		}
+		if len(c.dash.Stations) == 0 {
+			emptyMsg := tview.NewTextView().SetText("\n\n" + c.loc.T("no_pcs")).SetTextAlign(tview.AlignCenter)
+			c.mainFlex.AddItem(emptyMsg, 0, 1, false)
 			return
 		}
 
-		for _, pcID := range connectedPCs {
+		// Right-to-left locales read the columns mirrored
+		rtl := c.loc.RTL()
+		col := func(i int) int {
+			if rtl {
+				return 2 - i
+			}
+			return i
+		}
+		align := tview.AlignLeft
+		if rtl {
+			align = tview.AlignRight
+		}
+
+		for _, st := range c.dash.Stations {
+			pcID := st.Id
 			pcCol := tview.NewFlex().SetDirection(tview.FlexRow)
-			pcCol.SetBorder(true).SetTitle(fmt.Sprintf(" %s ", pcID)).SetBorderAttributes(tcell.AttrBold).SetBorderPadding(0, 0, 1, 1)
+			pcCol.SetBorder(true).SetTitle(fmt.Sprintf(" %s%s ", pcID, c.lockLabel(st))).SetBorderAttributes(tcell.AttrBold).SetBorderPadding(0, 0, 1, 1)
 
 			table := tview.NewTable().SetBorders(false).SetSelectable(true, false)
 			table.SetSelectedStyle(tcell.StyleDefault.Background(tcell.ColorNone).Foreground(tcell.ColorGreen))
 			table.SetTitle(pcID)
 
-			table.SetCell(0, 0, tview.NewTableCell("GAME").SetTextColor(tcell.ColorYellow).SetAttributes(tcell.AttrBold))
-			table.SetCell(0, 1, tview.NewTableCell("MIN").SetTextColor(tcell.ColorYellow).SetAttributes(tcell.AttrBold))
-			table.SetCell(0, 2, tview.NewTableCell("FEE").SetTextColor(tcell.ColorYellow).SetAttributes(tcell.AttrBold))
+			table.SetCell(0, col(0), tview.NewTableCell(c.loc.T("col.game")).SetTextColor(tcell.ColorYellow).SetAttributes(tcell.AttrBold).SetAlign(align))
+			table.SetCell(0, col(1), tview.NewTableCell(c.loc.T("col.min")).SetTextColor(tcell.ColorYellow).SetAttributes(tcell.AttrBold).SetAlign(align))
+			table.SetCell(0, col(2), tview.NewTableCell(c.loc.T("col.fee")).SetTextColor(tcell.ColorYellow).SetAttributes(tcell.AttrBold).SetAlign(align))
 
-			var sessions []models.Session
-			s.db.Where("pc_id = ? AND paid = ?", pcID, false).Order("start_time asc").Find(&sessions)
-
-			subTotal := decimal.Zero
 			row := 1
-			for _, sess := range sessions {
+			for _, sess := range st.Unpaid {
 				color := tcell.ColorGreen
-				if !sess.IsActive {
+				if !sess.Active {
 					color = tcell.ColorGray
 				}
-				table.SetCell(row, 0, tview.NewTableCell(sess.GameName).SetTextColor(color))
-				table.SetCell(row, 1, tview.NewTableCell(fmt.Sprintf("%d", sess.DurationMinutes)).SetTextColor(color))
-				table.SetCell(row, 2, tview.NewTableCell(sess.Fee.StringFixed(0)).SetTextColor(color))
-				subTotal = subTotal.Add(sess.Fee)
+				name := sess.GameTitle
+				if name == "" {
+					name = sess.GameName
+				}
+				table.SetCell(row, col(0), tview.NewTableCell(name).SetTextColor(color).SetAlign(align))
+				table.SetCell(row, col(1), tview.NewTableCell(c.loc.Digits(fmt.Sprintf("%d", sess.DurationMinutes))).SetTextColor(color).SetAlign(align))
+				table.SetCell(row, col(2), tview.NewTableCell(c.loc.Digits(money(sess.Fee))).SetTextColor(color).SetAlign(align))
 				row++
 			}
 
 			footerTable := tview.NewTable().SetBorders(false)
-			footerTable.SetCell(0, 0, tview.NewTableCell(" TOTAL").SetTextColor(tcell.ColorBlack).SetBackgroundColor(tcell.ColorYellow).SetAttributes(tcell.AttrBold))
-			footerTable.SetCell(0, 1, tview.NewTableCell(subTotal.StringFixed(0)+" ").SetTextColor(tcell.ColorBlack).SetBackgroundColor(tcell.ColorYellow).SetAlign(tview.AlignRight).SetExpansion(1))
+			totalLabel := tview.NewTableCell(" " + c.loc.T("total") + " ").SetTextColor(tcell.ColorBlack).SetBackgroundColor(tcell.ColorYellow).SetAttributes(tcell.AttrBold)
+			totalValue := tview.NewTableCell(" " + c.loc.Digits(money(st.UnpaidTotal)) + " ").SetTextColor(tcell.ColorBlack).SetBackgroundColor(tcell.ColorYellow).SetExpansion(1)
+			if rtl {
+				footerTable.SetCell(0, 0, totalValue.SetAlign(tview.AlignLeft))
+				footerTable.SetCell(0, 1, totalLabel)
+			} else {
+				footerTable.SetCell(0, 0, totalLabel)
+				footerTable.SetCell(0, 1, totalValue.SetAlign(tview.AlignRight))
+			}
 
 			pcCol.AddItem(table, 0, 1, true)
+			if st.Notice != "" {
+				pcCol.AddItem(tview.NewTextView().SetText(st.Notice).SetTextColor(tcell.ColorAqua).SetTextAlign(align), 1, 0, false)
+			}
 			pcCol.AddItem(footerTable, 1, 0, false)
 
 			pcCol.SetFocusFunc(func() { pcCol.SetBorderColor(tcell.ColorYellow) })
 			pcCol.SetBlurFunc(func() { pcCol.SetBorderColor(tcell.ColorWhite) })
 
-			s.pcTables = append(s.pcTables, table)
-			s.mainFlex.AddItem(pcCol, 32, 0, true)
+			c.pcTables = append(c.pcTables, table)
+			c.mainFlex.AddItem(pcCol, 32, 0, true)
 		}
-		s.mainFlex.AddItem(nil, 0, 1, false)
-		if len(s.pcTables) > 0 {
-			s.app.SetFocus(s.pcTables[0])
+		c.mainFlex.AddItem(nil, 0, 1, false)
+		// Don't steal focus from an open dialog, and keep it on the PC the operator picked
+		if front, _ := c.pages.GetFrontPage(); len(c.pcTables) > 0 && front == "main" {
+			focus := c.pcTables[0]
+			for _, t := range c.pcTables {
+				if t.GetTitle() == c.focused {
+					focus = t
+				}
+			}
+			c.app.SetFocus(focus)
 		}
 	})
 }
+
+// money drops the decimals the API sends; fees are whole currency units.
+func money(amount string) string {
+	d, err := decimal.NewFromString(amount)
+	if err != nil {
+		return amount
+	}
+	return d.StringFixed(0)
+}
+
+// lockLabel describes a PC's lock state for its column title.
+func (c *console) lockLabel(st *pb.Station) string {
+	if !c.cfg.LockEnabled {
+		return ""
+	}
+	switch {
+	case st.Open:
+		return " · " + c.loc.T("lock.open")
+	case st.Locked:
+		return " · " + c.loc.T("lock.locked")
+	default:
+		until := time.Unix(st.PrepaidUntil, 0)
+		return " · " + c.loc.T("lock.until", c.loc.Digits(until.Format("15:04")))
+	}
+}
+
+// toggleStation opens a locked PC or locks an open one.
+func (c *console) toggleStation(pcID string) {
+	open := false
+	if st := c.station(pcID); st != nil {
+		open = st.Locked
+	}
+	c.do(func(ctx context.Context) error {
+		_, err := c.admin.SetStationOpen(ctx, &pb.SetStationOpenRequest{PcId: pcID, Open: open})
+		return err
+	})
+}
+
+// closeGames closes whatever is running on a PC without settling it.
+func (c *console) closeGames(pcID string) {
+	c.do(func(ctx context.Context) error {
+		_, err := c.admin.SendCommand(ctx, &pb.SendCommandRequest{PcId: pcID, Action: pb.SendCommandRequest_CLOSE_GAMES})
+		return err
+	})
+}
+
+// showTimedDialog unlocks a PC for a fixed amount of time.
+func (c *console) showTimedDialog(pcID string) {
+	durations := []int{30, 60, 120}
+	labels := make([]string, 0, len(durations)+1)
+	for _, m := range durations {
+		labels = append(labels, c.loc.T("lock.minutes", m))
+	}
+
+	modal := tview.NewModal().
+		SetText(c.loc.T("lock.timed", pcID)).
+		AddButtons(append(labels, c.loc.T("dialog.cancel"))).
+		SetDoneFunc(func(index int, _ string) {
+			c.pages.RemovePage("timed")
+			if index >= 0 && index < len(durations) {
+				minutes := int32(durations[index])
+				c.do(func(ctx context.Context) error {
+					_, err := c.admin.StartTimedSession(ctx, &pb.StartTimedSessionRequest{PcId: pcID, Minutes: minutes})
+					return err
+				})
+			}
+			c.refreshUI()
+		})
+
+	c.pages.AddPage("timed", modal, false, true)
+}
+
+// showPaymentDialog asks for any discount and how the customer paid before settling
+// the PC. Each payment method is a button.
+func (c *console) showPaymentDialog(pcID string) {
+	form := tview.NewForm()
+	form.AddInputField(c.loc.T("dialog.discount"), "", 20, tview.InputFieldInteger, nil)
+	form.GetFormItem(0).(*tview.InputField).SetPlaceholder("0")
+
+	for _, method := range c.cfg.PaymentMethods {
+		form.AddButton(c.loc.Method(method), func() {
+			req := &pb.RecordPaymentRequest{PcId: pcID, Method: method}
+			req.Discount = form.GetFormItem(0).(*tview.InputField).GetText()
+			c.do(func(ctx context.Context) error {
+				_, err := c.admin.RecordPayment(ctx, req)
+				return err
+			})
+			c.pages.RemovePage("payment")
+			c.refreshUI()
+		})
+	}
+	form.AddButton(c.loc.T("dialog.cancel"), func() {
+		c.pages.RemovePage("payment")
+		c.refreshUI()
+	})
+
+	form.SetBorder(true).SetTitle(" " + c.loc.T("dialog.settle", pcID) + " ")
+	c.pages.AddPage("payment", centered(form, 60, 7), true, true)
+}
+
+// showClassifyDialog walks the operator through executables the catalog hasn't seen before.
+func (c *console) showClassifyDialog() {
+	go func() {
+		ctx, cancel := c.rpcContext()
+		defer cancel()
+		resp, err := c.admin.ListPendingGames(ctx, &pb.ListPendingGamesRequest{})
+		c.app.QueueUpdateDraw(func() {
+			if err != nil {
+				c.setStatus(err)
+				return
+			}
+			if len(resp.Games) == 0 {
+				modal := tview.NewModal().SetText(c.loc.T("classify.none")).
+					AddButtons([]string{"OK"}).
+					SetDoneFunc(func(int, string) { c.pages.RemovePage("classify") })
+				c.pages.AddPage("classify", modal, false, true)
+				return
+			}
+			c.classifyNext(resp.Games, 0)
+		})
+	}()
+}
+
+func (c *console) classifyNext(pending []*pb.Game, i int) {
+	c.pages.RemovePage("classify")
+	if i >= len(pending) {
+		c.refreshUI()
+		return
+	}
+	game := pending[i]
+	classes := c.cfg.RateClasses
+	current := 0
+	for j, class := range classes {
+		if class == game.RateClass {
+			current = j
+		}
+	}
+
+	form := tview.NewForm()
+	form.AddInputField(c.loc.T("classify.name"), game.Title, 32, nil, nil)
+	form.AddInputField(c.loc.T("classify.genre"), game.Genre, 32, nil, nil)
+	form.AddDropDown(c.loc.T("classify.rate"), classes, current, nil)
+
+	form.AddButton(c.loc.T("classify.save"), func() {
+		req := &pb.ClassifyGameRequest{
+			Executable: game.Executable,
+			Title:      form.GetFormItem(0).(*tview.InputField).GetText(),
+			Genre:      form.GetFormItem(1).(*tview.InputField).GetText(),
+		}
+		_, req.RateClass = form.GetFormItem(2).(*tview.DropDown).GetCurrentOption()
+		c.do(func(ctx context.Context) error {
+			_, err := c.admin.ClassifyGame(ctx, req)
+			return err
+		})
+		c.classifyNext(pending, i+1)
+	})
+	form.AddButton(c.loc.T("classify.ignore"), func() {
+		req := &pb.ClassifyGameRequest{Executable: game.Executable, NotGame: true}
+		c.do(func(ctx context.Context) error {
+			_, err := c.admin.ClassifyGame(ctx, req)
+			return err
+		})
+		c.classifyNext(pending, i+1)
+	})
+	form.AddButton(c.loc.T("classify.skip"), func() { c.classifyNext(pending, i+1) })
+	form.AddButton(c.loc.T("dialog.cancel"), func() {
+		c.pages.RemovePage("classify")
+		c.refreshUI()
+	})
+
+	form.SetBorder(true).SetTitle(c.loc.T("classify.title", game.Executable))
+	c.pages.AddPage("classify", centered(form, 60, 11), true, true)
+}
+
+// centered wraps a primitive so it floats in the middle of the screen like a tview.Modal.
+func centered(p tview.Primitive, width, height int) tview.Primitive {
+	return tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(p, height, 1, true).
+			AddItem(nil, 0, 1, false), width, 1, true).
+		AddItem(nil, 0, 1, false)
+}
+
+// showIdleDialog edits a station's idle overrides. Blank fields use the server default.
+func (c *console) showIdleDialog(pcID string) {
+	defaults := c.cfg.IdleDefaults
+	current := &pb.IdlePolicy{}
+	if st := c.station(pcID); st != nil && st.IdleOverrides != nil {
+		current = st.IdleOverrides
+	}
+
+	labels := []string{c.loc.T("idle.warn"), c.loc.T("idle.pause"), c.loc.T("idle.lock")}
+	values := []*int32{current.WarnMinutes, current.PauseMinutes, current.LockMinutes}
+	fallbacks := []int32{defaults.GetWarnMinutes(), defaults.GetPauseMinutes(), defaults.GetLockMinutes()}
+
+	form := tview.NewForm()
+	for i, label := range labels {
+		value := ""
+		if values[i] != nil {
+			value = strconv.Itoa(int(*values[i]))
+		}
+		form.AddInputField(label, value, 20, tview.InputFieldInteger, nil)
+		form.GetFormItem(i).(*tview.InputField).SetPlaceholder(c.loc.T("idle.default", fallbacks[i]))
+	}
+
+	form.AddButton(c.loc.T("dialog.save"), func() {
+		var minutes [3]*int32
+		for i := range minutes {
+			text := form.GetFormItem(i).(*tview.InputField).GetText()
+			if n, err := strconv.Atoi(text); err == nil && n >= 0 {
+				v := int32(n)
+				minutes[i] = &v
+			}
+		}
+		policy := &pb.IdlePolicy{WarnMinutes: minutes[0], PauseMinutes: minutes[1], LockMinutes: minutes[2]}
+		c.do(func(ctx context.Context) error {
+			_, err := c.admin.SetIdlePolicy(ctx, &pb.SetIdlePolicyRequest{PcId: pcID, Policy: policy})
+			return err
+		})
+		c.pages.RemovePage("idle")
+		c.refreshUI()
+	})
+	form.AddButton(c.loc.T("dialog.cancel"), func() {
+		c.pages.RemovePage("idle")
+		c.refreshUI()
+	})
+
+	form.SetBorder(true).SetTitle(c.loc.T("idle.title", pcID))
+	c.pages.AddPage("idle", centered(form, 60, 11), true, true)
+}
@@ -0,0 +1,272 @@
+package main
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	pb "github.com/Mohammad-Mahdi82/NexusOps/pkg/monitor"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+//go:embed web
+var webFiles embed.FS
+
+// webServer is the phone-friendly dashboard. It goes through the same adminServer
+// methods and the same operator auth as the console, so both always agree.
+type webServer struct {
+	s     *server
+	admin *adminServer
+}
+
+// newWebHandler serves the page and the small JSON API it uses.
+func newWebHandler(s *server, admin *adminServer) http.Handler {
+	w := &webServer{s: s, admin: admin}
+	static, _ := fs.Sub(webFiles, "web")
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /", http.FileServer(http.FS(static)))
+	mux.HandleFunc("GET /api/dashboard", w.authed(w.dashboard))
+	mux.HandleFunc("GET /api/events", w.authedEvents(w.events))
+	mux.HandleFunc("POST /api/stations/{id}/pay", w.authed(sameOrigin(w.pay)))
+	mux.HandleFunc("POST /api/stations/{id}/close-games", w.authed(sameOrigin(w.closeGames)))
+	w.registerREST(mux)
+	return mux
+}
+
+// authed checks the caller like the admin API does and passes the operator on in the context.
+func (w *webServer) authed(h http.HandlerFunc) http.HandlerFunc {
+	return w.authorize(h, false)
+}
+
+// authedEvents is authed for the event stream. EventSource can't set headers, so the
+// token may also come as ?token=; no other route takes it, to keep tokens out of URLs
+// and access logs.
+func (w *webServer) authedEvents(h http.HandlerFunc) http.HandlerFunc {
+	return w.authorize(h, true)
+}
+
+func (w *webServer) authorize(h http.HandlerFunc, queryToken bool) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		authorization := r.Header.Get("Authorization")
+		if authorization == "" && queryToken && r.URL.Query().Has("token") {
+			authorization = "Bearer " + r.URL.Query().Get("token")
+		}
+		// Without tokens the loopback address is the only credential, so a page from
+		// another site that rebinds its name to 127.0.0.1 must not get in either
+		if len(w.s.cfg.Admin.Tokens) == 0 && !loopbackHost(r.Host) {
+			writeError(rw, status.Error(codes.PermissionDenied, "open the dashboard as localhost until admin tokens are configured"))
+			return
+		}
+		operator, err := w.s.operatorFor(authorization, r.RemoteAddr)
+		if err != nil {
+			writeError(rw, err)
+			return
+		}
+		h(rw, r.WithContext(withOperator(r.Context(), operator)))
+	}
+}
+
+// loopbackHost tells whether a Host header names this machine.
+func loopbackHost(hostport string) bool {
+	host, _, err := net.SplitHostPort(hostport)
+	if err != nil {
+		host = hostport
+	}
+	host = strings.Trim(host, "[]")
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// sameOrigin guards routes that change something against cross-site requests: a page
+// on another site can make the browser send a form POST, but it can't make it claim to
+// be same-origin or send a JSON content type without CORS letting it.
+func sameOrigin(h http.HandlerFunc) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		if err := checkSameOrigin(r); err != nil {
+			writeError(rw, err)
+			return
+		}
+		h(rw, r)
+	}
+}
+
+func checkSameOrigin(r *http.Request) error {
+	// "none" is a request the user made directly, not one a page made
+	if site := r.Header.Get("Sec-Fetch-Site"); site != "" && site != "same-origin" && site != "none" {
+		return status.Error(codes.PermissionDenied, "cross-site request refused")
+	}
+	if origin := r.Header.Get("Origin"); origin != "" {
+		u, err := url.Parse(origin)
+		if err != nil || u.Host != r.Host {
+			return status.Error(codes.PermissionDenied, "cross-origin request refused")
+		}
+	}
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "application/json" {
+		return status.Error(codes.InvalidArgument, "Content-Type must be application/json")
+	}
+	return nil
+}
+
+func (w *webServer) dashboard(rw http.ResponseWriter, r *http.Request) {
+	w.s.mu.Lock()
+	d := w.s.dashboard()
+	w.s.mu.Unlock()
+	writeProto(rw, d)
+}
+
+// sseKeepalive keeps idle event streams from being cut by proxies and phone browsers.
+const sseKeepalive = 30 * time.Second
+
+// events is WatchEvents as Server-Sent Events: the event type is the SSE event name and
+// the data is the Event as JSON.
+func (w *webServer) events(rw http.ResponseWriter, r *http.Request) {
+	flusher, ok := rw.(http.Flusher)
+	if !ok {
+		http.Error(rw, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.s.mu.Lock()
+	events := w.s.subscribe()
+	snapshot := &pb.Event{Type: pb.Event_SNAPSHOT, Time: time.Now().Unix(), Dashboard: w.s.dashboard()}
+	w.s.mu.Unlock()
+	defer w.s.unsubscribe(events)
+
+	rw.Header().Set("Content-Type", "text/event-stream")
+	rw.Header().Set("Cache-Control", "no-cache")
+	send := func(ev *pb.Event) error {
+		data, err := jsonOptions.Marshal(ev)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(rw, "event: %s\ndata: %s\n\n", ev.Type, data); err != nil {
+			return err
+		}
+		flusher.Flush()
+		return nil
+	}
+	if send(snapshot) != nil {
+		return
+	}
+
+	keepalive := time.NewTicker(sseKeepalive)
+	defer keepalive.Stop()
+	for {
+		select {
+		case ev, ok := <-events:
+			if !ok {
+				return // Fell behind; EventSource reconnects and gets a new snapshot
+			}
+			if send(ev) != nil {
+				return
+			}
+		case <-keepalive.C:
+			fmt.Fprint(rw, ": keepalive\n\n")
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func (w *webServer) pay(rw http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Method   string `json:"method"`   // Empty for the default method
+		Discount string `json:"discount"` // Empty for none
+	}
+	if err := json.NewDecoder(io.LimitReader(r.Body, 1<<16)).Decode(&body); err != nil && err != io.EOF {
+		writeError(rw, status.Errorf(codes.InvalidArgument, "invalid JSON body: %v", err))
+		return
+	}
+	resp, err := w.admin.RecordPayment(r.Context(), &pb.RecordPaymentRequest{
+		PcId:     r.PathValue("id"),
+		Method:   body.Method,
+		Discount: body.Discount,
+	})
+	if err != nil {
+		writeError(rw, err)
+		return
+	}
+	writeProto(rw, resp)
+}
+
+func (w *webServer) closeGames(rw http.ResponseWriter, r *http.Request) {
+	resp, err := w.admin.SendCommand(r.Context(), &pb.SendCommandRequest{
+		PcId:   r.PathValue("id"),
+		Action: pb.SendCommandRequest_CLOSE_GAMES,
+	})
+	if err != nil {
+		writeError(rw, err)
+		return
+	}
+	writeProto(rw, resp)
+}
+
+var jsonOptions = protojson.MarshalOptions{EmitUnpopulated: true}
+
+func writeProto(rw http.ResponseWriter, m proto.Message) {
+	data, err := jsonOptions.Marshal(m)
+	if err != nil {
+		writeError(rw, err)
+		return
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	rw.Write(data)
+}
+
+// writeError turns an admin API status into the matching HTTP one, with the gRPC code
+// and message as a JSON body.
+func writeError(rw http.ResponseWriter, err error) {
+	code := http.StatusInternalServerError
+	switch status.Code(err) {
+	case codes.InvalidArgument:
+		code = http.StatusBadRequest
+	case codes.Unauthenticated:
+		code = http.StatusUnauthorized
+	case codes.PermissionDenied:
+		code = http.StatusForbidden
+	case codes.NotFound:
+		code = http.StatusNotFound
+	case codes.FailedPrecondition:
+		code = http.StatusConflict
+	}
+	st := status.Convert(err)
+	body, _ := json.Marshal(map[string]string{"code": st.Code().String(), "message": st.Message()})
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(code)
+	rw.Write(body)
+}
+
+// serveWeb runs the dashboard or the metrics until shutdown. A failure to start is
+// logged, not fatal: billing matters more than either.
+func serveWeb(srv *http.Server) {
+	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		slog.Error("HTTP listener disabled", "listen", srv.Addr, "err", err)
+	}
+}
+
+func stopWeb(srv *http.Server) {
+	if srv == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	srv.Shutdown(ctx)
+}